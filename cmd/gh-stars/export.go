@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/export"
+)
+
+// runExport implements `gh-stars export`. It writes the same selection as
+// `search` (or the whole cache without a query) in a publishable format.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	selection := addSelectionFlags(fs)
+	format := fs.String("format", "", "Export format: "+strings.Join(export.Formats, ", ")+" (default: from -o, else markdown)")
+	output := fs.String("o", "", "Output file (default: stdout)")
+	groupBy := fs.String("group", "language", "Group Markdown, HTML and OPML output by: "+strings.Join(export.GroupModes, ", "))
+	title := fs.String("title", "GitHub Stars", "Document title")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gh-stars export [flags] [query]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format == "" {
+		*format = "markdown"
+		if guessed, ok := export.FormatFromPath(*output); ok {
+			*format = guessed
+		}
+	}
+	// Before -o is opened, so a typo never truncates the file.
+	if err := export.ValidateFormat(*format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if err := export.ValidateGroupBy(*groupBy); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	records, annotations, err := selection.load(strings.Join(fs.Args(), " "))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	repos := make([]data.Repo, 0, len(records))
+	for _, r := range records {
+		repos = append(repos, r.repo)
+	}
+
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if *output != "" && *output != "-" {
+		file, err = os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "export failed:", err)
+			return 1
+		}
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+
+	opts := export.Options{Format: *format, GroupBy: *groupBy, Title: *title, Annotations: annotations}
+	err = export.Write(buffered, repos, opts)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export failed:", err)
+		return 1
+	}
+	if file != nil {
+		fmt.Fprintf(os.Stderr, "exported %d repos to %s\n", len(repos), *output)
+	}
+	return 0
+}
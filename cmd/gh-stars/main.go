@@ -1,72 +1,271 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	gh "github.com/cli/go-gh/v2/pkg/api"
 
+	"github.com/viniciussoares/github-stars-tui/internal/config"
 	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/search"
 	"github.com/viniciussoares/github-stars-tui/internal/ui"
+	"github.com/viniciussoares/github-stars-tui/internal/workspace"
 )
 
 func main() {
-	pageSize := flag.Int("page-size", 100, "Stars to fetch per request (max 100)")
-	defaultPath := defaultCachePath()
-	cachePath := flag.String("cache", defaultPath, "Cache file path")
-	refresh := flag.Bool("refresh", false, "Force refresh on startup")
-	syncInterval := flag.Duration("sync-interval", 48*time.Hour, "Background refresh interval")
-	flag.Parse()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "list", "search":
+			os.Exit(runQuery(os.Args[1], os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "pick":
+			os.Exit(runPick(os.Args[2:]))
+		}
+	}
 
-	if *pageSize <= 0 || *pageSize > 100 {
-		fmt.Fprintln(os.Stderr, "page-size must be between 1 and 100")
+	flags := addTUIFlags(flag.CommandLine, loadConfig())
+	flag.Parse()
+	if err := flags.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}
 
-	client, err := gh.DefaultGraphQLClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	model, err := flags.model(ctx, ui.Options{})
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "could not create GitHub client:", err)
-		fmt.Fprintln(os.Stderr, "make sure `gh auth login` has been run")
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	program := tea.NewProgram(model, tea.WithAltScreen())
+	flags.startSync(program)
+	final, err := program.Run()
+	cancel()
+	flags.wait()
+	if final, ok := final.(ui.Model); ok {
+		final.Wait()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to run UI:", err)
+		os.Exit(1)
+	}
+}
+
+// tuiFlags are shared by the interactive commands.
+type tuiFlags struct {
+	config          config.Config
+	source          *sourceFlags
+	sortMode        *string
+	pageSize        *int
+	cachePath       *string
+	refresh         *bool
+	syncInterval    *time.Duration
+	hydrateInterval *time.Duration
+	reconcile       *bool
+	cloneDir        *string
+	cloneProtocol   *string
+
+	// syncer owns the cache file; pendingSync starts its background sync
+	// once the program is created.
+	syncer      *ui.Syncer
+	pendingSync func(*tea.Program)
+}
 
-	cache, err := data.LoadCache(*cachePath)
+// addTUIFlags registers the interactive flags, taking their defaults from
+// the config file.
+func addTUIFlags(fs *flag.FlagSet, cfg config.Config) *tuiFlags {
+	pageSize := 100
+	if cfg.PageSize != 0 {
+		pageSize = cfg.PageSize
+	}
+	syncInterval := 48 * time.Hour
+	if cfg.SyncInterval != nil {
+		syncInterval = *cfg.SyncInterval
+	}
+	hydrateInterval := 7 * 24 * time.Hour
+	if cfg.HydrateInterval != nil {
+		hydrateInterval = *cfg.HydrateInterval
+	}
+	sortMode := "default"
+	if cfg.Sort != "" {
+		sortMode = cfg.Sort
+	}
+	cloneDir := workspace.DefaultLayout
+	if cfg.Clone.Dir != "" {
+		cloneDir = cfg.Clone.Dir
+	}
+	cloneProtocol := "https"
+	if cfg.Clone.Protocol != "" {
+		cloneProtocol = cfg.Clone.Protocol
+	}
+
+	return &tuiFlags{
+		config:          cfg,
+		source:          addSourceFlags(fs, cfg),
+		pageSize:        fs.Int("page-size", pageSize, "Stars to fetch per request (max 100)"),
+		cachePath:       fs.String("cache", configCachePath(cfg), "Cache file path"),
+		refresh:         fs.Bool("refresh", false, "Force refresh on startup"),
+		syncInterval:    fs.Duration("sync-interval", syncInterval, "Background refresh interval"),
+		hydrateInterval: fs.Duration("hydrate-interval", hydrateInterval, "Metadata refresh interval for already-cached repos"),
+		reconcile:       fs.Bool("reconcile", cfg.Reconcile, "Walk all starred pages on background sync to detect removed and renamed repos"),
+		sortMode:        fs.String("sort", sortMode, "Initial sort mode: "+strings.Join(search.SortModes, ", ")),
+		cloneDir:        fs.String("clone-dir", cloneDir, "Where to clone repos; {host}, {owner} and {name} are replaced"),
+		cloneProtocol:   fs.String("clone-protocol", cloneProtocol, "Clone over https or ssh"),
+	}
+}
+
+func (f *tuiFlags) validate() error {
+	if *f.pageSize <= 0 || *f.pageSize > 100 {
+		return errors.New("page-size must be between 1 and 100")
+	}
+	if err := search.ValidateSortMode(*f.sortMode); err != nil {
+		return err
+	}
+	return workspace.ValidateProtocol(*f.cloneProtocol)
+}
+
+// model loads the cache and annotations, prepares the background sync when
+// the cache is stale and builds the UI model. opts carries the command-specific
+// settings; everything else is filled in from the flags. Cancelling ctx
+// aborts the background sync and the UI's requests.
+func (f *tuiFlags) model(ctx context.Context, opts ui.Options) (ui.Model, error) {
+	source, err := f.source.open(true)
+	if err != nil {
+		return ui.Model{}, err
+	}
+
+	pageSize := *f.pageSize
+	cachePath := data.SourceCachePath(*f.cachePath, source)
+	f.syncer = ui.NewSyncer(source, cachePath, pageSize)
+	legacyPath := ""
+	if cachePath == defaultCachePath() {
+		legacyPath = data.LegacyCachePath
+	}
+	var versionErr *data.CacheVersionError
+	if err := data.UpgradeCache(cachePath, legacyPath); errors.As(err, &versionErr) {
+		return ui.Model{}, err
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: cache migration failed:", err)
+	}
+	cache, err := f.syncer.Load()
+	if errors.As(err, &versionErr) {
+		return ui.Model{}, err
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "warning: cache load failed:", err)
 	}
-	if *cachePath == defaultPath && cache.SavedAt.IsZero() {
-		legacyPath := ".cache/gh-stars.json"
-		legacy, err := data.LoadCache(legacyPath)
-		if err == nil && len(legacy.Repos) > 0 {
-			if err := data.SaveCache(*cachePath, legacy.Repos); err != nil {
-				fmt.Fprintln(os.Stderr, "warning: cache migration failed:", err)
-			} else {
-				cache = legacy
-			}
-		}
+
+	annotationsPath := data.AnnotationsPath(cachePath)
+	annotations, err := data.LoadAnnotations(annotationsPath)
+	if err != nil {
+		// Don't risk overwriting notes we failed to read.
+		fmt.Fprintln(os.Stderr, "warning: annotations load failed, edits will not be saved:", err)
+		annotationsPath = ""
 	}
 
 	backgroundSync := false
-	if *cachePath != "" && len(cache.Repos) > 0 && data.IsStale(cache, *syncInterval) && !*refresh {
+	if cachePath != "" && len(cache.Repos) > 0 && data.IsStale(cache, *f.syncInterval) && !*f.refresh {
 		backgroundSync = true
-		go func() {
-			if err := data.RefreshCache(client, *pageSize, *cachePath, cache); err != nil {
-				fmt.Fprintln(os.Stderr, "background refresh failed:", err)
-			}
-		}()
+		cached, reconcile := cache.Repos, *f.reconcile
+		f.pendingSync = func(program *tea.Program) {
+			f.syncer.Start(ctx, program, cached, reconcile)
+		}
 	}
 
-	fetchOnStart := *refresh || len(cache.Repos) == 0
-	model := ui.NewModel(client, *pageSize, *cachePath, cache.Repos, fetchOnStart, backgroundSync)
-	program := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := program.Run(); err != nil {
-		fmt.Fprintln(os.Stderr, "failed to run UI:", err)
-		os.Exit(1)
+	opts.Context = ctx
+	opts.PageSize = pageSize
+	opts.CachePath = cachePath
+	opts.FetchOnStart = *f.refresh || len(cache.Repos) == 0
+	opts.BackgroundSync = backgroundSync
+	opts.Syncer = f.syncer
+	opts.HydrateInterval = *f.hydrateInterval
+	opts.InactiveAfter = configInactiveAfter(f.config)
+	opts.Annotations = annotations
+	opts.AnnotationsPath = annotationsPath
+	opts.CloneLayout = *f.cloneDir
+	opts.CloneProtocol = *f.cloneProtocol
+	opts.SortMode = *f.sortMode
+	opts.Keys = f.config.UIKeyMap()
+	opts.Theme = f.config.UITheme()
+	layout := f.config.UILayout()
+	opts.Layout = &layout
+	return ui.NewModel(source, cache, opts), nil
+}
+
+// startSync runs the background sync prepared by model, if any.
+func (f *tuiFlags) startSync(program *tea.Program) {
+	if f.pendingSync != nil {
+		f.pendingSync(program)
+	}
+}
+
+// wait blocks until the background sync has stopped, so the process never
+// exits while it still reports to the program.
+func (f *tuiFlags) wait() {
+	if f.syncer != nil {
+		f.syncer.Wait()
+	}
+}
+
+// sourceFlags pick whose stars to show and on which GitHub host.
+type sourceFlags struct {
+	host *string
+	user *string
+}
+
+func addSourceFlags(fs *flag.FlagSet, cfg config.Config) *sourceFlags {
+	return &sourceFlags{
+		host: fs.String("host", cfg.Host, "GitHub host, e.g. a GitHub Enterprise Server (default: gh's default host)"),
+		user: fs.String("user", "", "Show another user's public stars instead of your own"),
+	}
+}
+
+// open resolves the source. Without connect it has no client, which is
+// enough to find its cache.
+func (f *sourceFlags) open(connect bool) (data.StarSource, error) {
+	host := data.ResolveHost(*f.host)
+	var client *gh.GraphQLClient
+	if connect {
+		var err error
+		client, err = gh.NewGraphQLClient(gh.ClientOptions{Host: host})
+		if err != nil {
+			return nil, fmt.Errorf("could not create GitHub client for %s: %w\nmake sure `gh auth login --hostname %s` has been run", host, err, host)
+		}
+	}
+	return data.NewStarSource(client, host, *f.user), nil
+}
+
+// loadConfig reads the config file, exiting on invalid settings so they are
+// never silently ignored.
+func loadConfig() config.Config {
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid config:", err)
+		os.Exit(2)
+	}
+	return cfg
+}
+
+func configCachePath(cfg config.Config) string {
+	if cfg.Cache != nil {
+		return *cfg.Cache
+	}
+	return defaultCachePath()
+}
+
+func configInactiveAfter(cfg config.Config) time.Duration {
+	if cfg.InactiveAfter != nil {
+		return *cfg.InactiveAfter
 	}
+	return data.DefaultInactiveAfter
 }
 
 func defaultCachePath() string {
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/ui"
+	"github.com/viniciussoares/github-stars-tui/internal/workspace"
+)
+
+var pickFormats = []string{"url", "clone-url", "ssh-url", "name", "json"}
+
+// pickRepo is the data available to -format templates.
+type pickRepo struct {
+	data.Repo
+	Owner    string
+	CloneURL string
+	SSHURL   string
+}
+
+func newPickRepo(repo data.Repo) pickRepo {
+	owner, _, _ := strings.Cut(repo.NameWithOwner, "/")
+	return pickRepo{
+		Repo:     repo,
+		Owner:    owner,
+		CloneURL: workspace.CloneURL(repo, "https"),
+		SSHURL:   workspace.CloneURL(repo, "ssh"),
+	}
+}
+
+// runPick implements `gh-stars pick`: the TUI runs on the terminal and the
+// chosen repo is written to stdout, so the command can sit in a pipeline.
+func runPick(args []string) int {
+	fs := flag.NewFlagSet("pick", flag.ContinueOnError)
+	flags := addTUIFlags(fs, loadConfig())
+	format := fs.String("format", "url", "Output: "+strings.Join(pickFormats, ", ")+", or a Go template such as '{{.Owner}} {{.Name}}'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gh-stars pick [flags] [query]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := flags.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	write, err := pickWriter(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	// stdout belongs to the caller, so draw on the terminal directly and fall
+	// back to stderr when there is none.
+	var screen io.Writer = os.Stderr
+	programOpts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithOutput(os.Stderr)}
+	if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+		defer tty.Close()
+		screen = tty
+		programOpts = []tea.ProgramOption{tea.WithAltScreen(), tea.WithInput(tty), tea.WithOutput(tty)}
+	}
+	lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(screen))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer flags.wait()
+	defer cancel()
+	model, err := flags.model(ctx, ui.Options{Query: strings.Join(fs.Args(), " "), PickMode: true})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	program := tea.NewProgram(model, programOpts...)
+	flags.startSync(program)
+	final, err := program.Run()
+	cancel()
+	if final, ok := final.(ui.Model); ok {
+		final.Wait()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to run UI:", err)
+		return 1
+	}
+
+	picked := final.(ui.Model).Picked()
+	if len(picked) == 0 {
+		return 1
+	}
+	out := bufio.NewWriter(os.Stdout)
+	for _, repo := range picked {
+		if err := write(out, repo); err != nil {
+			fmt.Fprintln(os.Stderr, "write failed:", err)
+			return 1
+		}
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "write failed:", err)
+		return 1
+	}
+	return 0
+}
+
+// pickWriter returns a function printing one repo per line in format.
+func pickWriter(format string) (func(io.Writer, data.Repo) error, error) {
+	line := func(text func(pickRepo) string) func(io.Writer, data.Repo) error {
+		return func(w io.Writer, repo data.Repo) error {
+			_, err := fmt.Fprintln(w, text(newPickRepo(repo)))
+			return err
+		}
+	}
+
+	switch format {
+	case "url":
+		return line(func(r pickRepo) string { return r.URL }), nil
+	case "clone-url":
+		return line(func(r pickRepo) string { return r.CloneURL }), nil
+	case "ssh-url":
+		return line(func(r pickRepo) string { return r.SSHURL }), nil
+	case "name":
+		return line(func(r pickRepo) string { return r.NameWithOwner }), nil
+	case "json":
+		fields, err := parseFields("name,repo,owner,description,url,stars,language,topics,fork,starred_at,updated_at,id")
+		if err != nil {
+			return nil, err
+		}
+		return func(w io.Writer, repo data.Repo) error {
+			encoded, err := recordJSON(fields, record{repo: repo})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "%s\n", encoded)
+			return err
+		}, nil
+	}
+
+	if !strings.Contains(format, "{{") {
+		return nil, fmt.Errorf("unknown format %q (want %s, or a template)", format, strings.Join(pickFormats, ", "))
+	}
+	tmpl, err := template.New("pick").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	return func(w io.Writer, repo data.Repo) error {
+		if err := tmpl.Execute(w, newPickRepo(repo)); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w)
+		return err
+	}, nil
+}
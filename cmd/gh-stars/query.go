@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/search"
+)
+
+type record struct {
+	repo       data.Repo
+	annotation data.Annotation
+	lists      []string
+	health     data.Health
+}
+
+type outputField struct {
+	name  string
+	value func(r record) any
+}
+
+var outputFields = []outputField{
+	{"name", func(r record) any { return r.repo.NameWithOwner }},
+	{"repo", func(r record) any { return r.repo.Name }},
+	{"owner", func(r record) any { owner, _, _ := strings.Cut(r.repo.NameWithOwner, "/"); return owner }},
+	{"description", func(r record) any { return r.repo.Description }},
+	{"url", func(r record) any { return r.repo.URL }},
+	{"stars", func(r record) any { return r.repo.Stars }},
+	{"language", func(r record) any { return r.repo.PrimaryLanguage }},
+	{"topics", func(r record) any { return nonNil(r.repo.Topics) }},
+	{"fork", func(r record) any { return r.repo.IsFork }},
+	{"archived", func(r record) any { return r.repo.IsArchived }},
+	{"mirror", func(r record) any { return r.repo.IsMirror }},
+	{"health", func(r record) any { return r.health.String() }},
+	{"starred_at", func(r record) any { return r.repo.StarredAt }},
+	{"updated_at", func(r record) any { return r.repo.UpdatedAt }},
+	{"pushed_at", func(r record) any { return r.repo.PushedAt }},
+	{"id", func(r record) any { return r.repo.ID }},
+	{"tags", func(r record) any { return nonNil(r.annotation.Tags) }},
+	{"note", func(r record) any { return r.annotation.Note }},
+	{"lists", func(r record) any { return nonNil(r.lists) }},
+}
+
+const defaultFields = "name,stars,language,description"
+
+// selectionFlags are shared by the subcommands that read a filtered, sorted
+// slice of the cache.
+type selectionFlags struct {
+	source    *sourceFlags
+	cachePath *string
+	syncFirst *bool
+	pageSize  *int
+	sortMode  *string
+	limit     *int
+	listName  *string
+
+	inactiveAfter time.Duration
+}
+
+func addSelectionFlags(fs *flag.FlagSet) *selectionFlags {
+	cfg := loadConfig()
+	pageSize := 100
+	if cfg.PageSize != 0 {
+		pageSize = cfg.PageSize
+	}
+
+	return &selectionFlags{
+		source:    addSourceFlags(fs, cfg),
+		cachePath: fs.String("cache", configCachePath(cfg), "Cache file path"),
+		syncFirst: fs.Bool("sync", false, "Fetch new stars before reading the cache"),
+		pageSize:  fs.Int("page-size", pageSize, "Stars to fetch per request when syncing (max 100)"),
+		sortMode:  fs.String("sort", cfg.Sort, "Sort mode: "+strings.Join(search.SortModes, ", ")),
+		limit:     fs.Int("limit", 0, "Maximum number of results (0 for all)"),
+		listName:  fs.String("list", "", "Only include repos in this star list (name or slug)"),
+
+		inactiveAfter: configInactiveAfter(cfg),
+	}
+}
+
+// load applies queryText to the cache exactly like the TUI does and returns
+// the matching repos in display order. Without a -sort flag or configured
+// sort, searches are ordered by relevance and everything else by star date.
+func (f *selectionFlags) load(queryText string) ([]record, data.Annotations, error) {
+	sortMode := *f.sortMode
+	if sortMode == "" {
+		sortMode = "default"
+		if strings.TrimSpace(queryText) != "" {
+			sortMode = "relevance"
+		}
+	}
+	if err := search.ValidateSortMode(sortMode); err != nil {
+		return nil, nil, err
+	}
+	query, err := search.Parse(queryText, f.inactiveAfter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	source, err := f.source.open(*f.syncFirst)
+	if err != nil {
+		return nil, nil, err
+	}
+	cachePath := data.SourceCachePath(*f.cachePath, source)
+	cache, err := data.LoadCache(cachePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cache load failed: %w", err)
+	}
+	if *f.syncFirst {
+		if *f.pageSize <= 0 || *f.pageSize > 100 {
+			return nil, nil, errors.New("page-size must be between 1 and 100")
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err := data.RefreshCache(ctx, source, *f.pageSize, cachePath, cache)
+		stop()
+		if err != nil {
+			return nil, nil, fmt.Errorf("sync failed: %w", err)
+		}
+		if cache, err = data.LoadCache(cachePath); err != nil {
+			return nil, nil, fmt.Errorf("cache load failed: %w", err)
+		}
+	}
+	if len(cache.Repos) == 0 {
+		return nil, nil, errors.New("cache is empty; run gh-stars once or pass -sync")
+	}
+
+	annotations, err := data.LoadAnnotations(data.AnnotationsPath(cachePath))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: annotations load failed:", err)
+	}
+
+	var keep func(data.Repo) bool
+	if *f.listName != "" {
+		list, ok := findList(cache.Lists, *f.listName)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown star list %q", *f.listName)
+		}
+		keep = func(repo data.Repo) bool { return list.Contains(repo.NameWithOwner) }
+	}
+
+	indices, results := search.Filter(cache.Repos, query, annotations, keep)
+	search.Sort(cache.Repos, indices, sortMode, results, f.inactiveAfter)
+	if *f.limit > 0 && len(indices) > *f.limit {
+		indices = indices[:*f.limit]
+	}
+
+	now := time.Now()
+	records := make([]record, 0, len(indices))
+	for _, idx := range indices {
+		repo := cache.Repos[idx]
+		records = append(records, record{
+			repo:       repo,
+			annotation: annotations[repo.NameWithOwner],
+			lists:      listNames(cache.Lists, repo.NameWithOwner),
+			health:     repo.Health(now, f.inactiveAfter),
+		})
+	}
+	return records, annotations, nil
+}
+
+// runQuery implements the non-interactive `list` and `search` subcommands.
+func runQuery(command string, args []string) int {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	selection := addSelectionFlags(fs)
+	format := fs.String("format", "table", "Output format: table, json, ndjson")
+	fieldList := fs.String("fields", defaultFields, "Comma-separated fields: "+fieldNames())
+	fs.Usage = func() {
+		if command == "search" {
+			fmt.Fprintln(fs.Output(), "usage: gh-stars search [flags] <query>")
+		} else {
+			fmt.Fprintln(fs.Output(), "usage: gh-stars list [flags]")
+		}
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	queryText := ""
+	if command == "search" {
+		queryText = strings.Join(fs.Args(), " ")
+		if strings.TrimSpace(queryText) == "" {
+			fs.Usage()
+			return 2
+		}
+	} else if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	fields, err := parseFields(*fieldList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	switch *format {
+	case "table", "json", "ndjson":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q (want table, json or ndjson)\n", *format)
+		return 2
+	}
+
+	records, _, err := selection.load(queryText)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	switch *format {
+	case "table":
+		err = writeTable(out, fields, records)
+	case "json":
+		err = writeJSON(out, fields, records)
+	case "ndjson":
+		err = writeNDJSON(out, fields, records)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "write failed:", err)
+		return 1
+	}
+	return 0
+}
+
+func parseFields(list string) ([]outputField, error) {
+	fields := []outputField{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, field := range outputFields {
+			if field.name == name {
+				fields = append(fields, field)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %q (want one of %s)", name, fieldNames())
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields selected")
+	}
+	return fields, nil
+}
+
+func fieldNames() string {
+	names := make([]string, 0, len(outputFields))
+	for _, field := range outputFields {
+		names = append(names, field.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func findList(lists []data.StarList, name string) (data.StarList, bool) {
+	for _, list := range lists {
+		if strings.EqualFold(list.Name, name) || strings.EqualFold(list.Slug, name) {
+			return list, true
+		}
+	}
+	return data.StarList{}, false
+}
+
+func listNames(lists []data.StarList, nameWithOwner string) []string {
+	names := []string{}
+	for _, list := range lists {
+		if list.Contains(nameWithOwner) {
+			names = append(names, list.Name)
+		}
+	}
+	return names
+}
+
+func writeTable(w io.Writer, fields []outputField, records []record) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	headers := make([]string, 0, len(fields))
+	for _, field := range fields {
+		headers = append(headers, strings.ToUpper(field.name))
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, r := range records {
+		cells := make([]string, 0, len(fields))
+		for _, field := range fields {
+			cells = append(cells, tableCell(field.value(r)))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func tableCell(value any) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.Format("2006-01-02")
+	case string:
+		v = strings.Join(strings.Fields(v), " ")
+		if v == "" {
+			return "-"
+		}
+		runes := []rune(v)
+		if len(runes) > 60 {
+			return string(runes[:57]) + "..."
+		}
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
+// recordJSON encodes the selected fields in the order they were requested.
+func recordJSON(fields []outputField, r record) ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(field.name)
+		value, err := json.Marshal(field.value(r))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+func writeJSON(w io.Writer, fields []outputField, records []record) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, r := range records {
+		line, err := recordJSON(fields, r)
+		if err != nil {
+			return err
+		}
+		sep := ",\n  "
+		if i == 0 {
+			sep = "\n  "
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	if len(records) > 0 {
+		_, err := io.WriteString(w, "\n]\n")
+		return err
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+func writeNDJSON(w io.Writer, fields []outputField, records []record) error {
+	for _, r := range records {
+		line, err := recordJSON(fields, r)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/viniciussoares/github-stars-tui/internal/search"
+	"github.com/viniciussoares/github-stars-tui/internal/ui"
+	"github.com/viniciussoares/github-stars-tui/internal/workspace"
+)
+
+// Config mirrors config.yaml. Unset fields keep the built-in defaults and
+// command-line flags override anything set here.
+type Config struct {
+	Host            string                `yaml:"host"`
+	PageSize        int                   `yaml:"page_size"`
+	Cache           *string               `yaml:"cache"`
+	SyncInterval    *time.Duration        `yaml:"sync_interval"`
+	HydrateInterval *time.Duration        `yaml:"hydrate_interval"`
+	InactiveAfter   *time.Duration        `yaml:"inactive_after"`
+	Reconcile       bool                  `yaml:"reconcile"`
+	Sort            string                `yaml:"sort"`
+	Clone           CloneConfig           `yaml:"clone"`
+	Layout          LayoutConfig          `yaml:"layout"`
+	Keys            map[string]keyList    `yaml:"keys"`
+	Theme           map[string]styleEntry `yaml:"theme"`
+
+	path   string
+	keyMap *ui.KeyMap
+	theme  map[string]ui.StyleOverride
+}
+
+type CloneConfig struct {
+	Dir      string `yaml:"dir"`
+	Protocol string `yaml:"protocol"`
+}
+
+type LayoutConfig struct {
+	PreviewMinWidth *int     `yaml:"preview_min_width"`
+	ListRatio       *float64 `yaml:"list_ratio"`
+	ListMinWidth    *int     `yaml:"list_min_width"`
+}
+
+// keyList accepts a single key or a list of keys.
+type keyList []string
+
+func (k *keyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*k = keyList{node.Value}
+		return nil
+	}
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+type styleEntry struct {
+	Foreground       *color `yaml:"foreground"`
+	Background       *color `yaml:"background"`
+	BorderForeground *color `yaml:"border_foreground"`
+	Bold             *bool  `yaml:"bold"`
+	Italic           *bool  `yaml:"italic"`
+	Underline        *bool  `yaml:"underline"`
+	Faint            *bool  `yaml:"faint"`
+}
+
+// color is either "#hex" / an ANSI number, or {light: ..., dark: ...}.
+type color ui.Color
+
+func (c *color) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = color{Light: node.Value}
+		return nil
+	}
+	var adaptive struct {
+		Light string `yaml:"light"`
+		Dark  string `yaml:"dark"`
+	}
+	if err := node.Decode(&adaptive); err != nil {
+		return err
+	}
+	if adaptive.Light == "" || adaptive.Dark == "" {
+		return fmt.Errorf("line %d: adaptive colors need both light and dark", node.Line)
+	}
+	*c = color{Light: adaptive.Light, Dark: adaptive.Dark}
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func validColor(value string) bool {
+	if hexColor.MatchString(value) {
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func DefaultPath() string {
+	if path := os.Getenv("GH_STARS_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil || dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "gh-stars", "config.yaml")
+}
+
+// Load reads and validates the config at path. A missing file is not an
+// error and yields the zero Config.
+func Load(path string) (Config, error) {
+	if path == "" {
+		return Config{}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Config{}, nil
+		}
+		return Config{}, err
+	}
+
+	cfg := Config{path: path}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return Config{}, fmt.Errorf("%s:\n  %s", path, strings.ReplaceAll(err.Error(), "\n", "\n  "))
+	}
+	return cfg, nil
+}
+
+// validate checks every setting, reporting all problems at once.
+func (c *Config) validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.PageSize != 0 && (c.PageSize < 0 || c.PageSize > 100) {
+		fail("page_size must be between 1 and 100")
+	}
+	if c.SyncInterval != nil && *c.SyncInterval < 0 {
+		fail("sync_interval must not be negative")
+	}
+	if c.HydrateInterval != nil && *c.HydrateInterval < 0 {
+		fail("hydrate_interval must not be negative")
+	}
+	if c.InactiveAfter != nil && *c.InactiveAfter <= 0 {
+		fail("inactive_after must be positive")
+	}
+	if c.Sort != "" {
+		if err := search.ValidateSortMode(c.Sort); err != nil {
+			fail("sort: %v", err)
+		}
+	}
+	if c.Clone.Protocol != "" {
+		if err := workspace.ValidateProtocol(c.Clone.Protocol); err != nil {
+			fail("clone.protocol: %v", err)
+		}
+	}
+	if c.Cache != nil {
+		expanded := expandHome(*c.Cache)
+		c.Cache = &expanded
+	}
+	if err := c.UILayout().Validate(); err != nil {
+		fail("layout: %v", err)
+	}
+
+	if len(c.Keys) > 0 {
+		overrides := make(map[string][]string, len(c.Keys))
+		for action, keys := range c.Keys {
+			overrides[action] = keys
+		}
+		keyMap, err := ui.NewKeyMap(overrides)
+		if err != nil {
+			fail("keys: %v", err)
+		} else {
+			c.keyMap = &keyMap
+		}
+	}
+
+	if len(c.Theme) > 0 {
+		names := make([]string, 0, len(c.Theme))
+		for name := range c.Theme {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		theme := make(map[string]ui.StyleOverride, len(c.Theme))
+		for _, name := range names {
+			entry := c.Theme[name]
+			for _, col := range []*color{entry.Foreground, entry.Background, entry.BorderForeground} {
+				if col == nil {
+					continue
+				}
+				for _, value := range []string{col.Light, col.Dark} {
+					if value != "" && !validColor(value) {
+						fail("theme.%s: invalid color %q (want #rgb, #rrggbb or 0-255)", name, value)
+					}
+				}
+			}
+			theme[name] = ui.StyleOverride{
+				Foreground:       (*ui.Color)(entry.Foreground),
+				Background:       (*ui.Color)(entry.Background),
+				BorderForeground: (*ui.Color)(entry.BorderForeground),
+				Bold:             entry.Bold,
+				Italic:           entry.Italic,
+				Underline:        entry.Underline,
+				Faint:            entry.Faint,
+			}
+		}
+		if _, err := ui.DefaultStyles().WithTheme(theme); err != nil {
+			fail("theme: %v", err)
+		} else {
+			c.theme = theme
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c Config) Path() string {
+	return c.path
+}
+
+func (c Config) UILayout() ui.Layout {
+	layout := ui.DefaultLayout()
+	if c.Layout.PreviewMinWidth != nil {
+		layout.PreviewMinWidth = *c.Layout.PreviewMinWidth
+	}
+	if c.Layout.ListRatio != nil {
+		layout.ListRatio = *c.Layout.ListRatio
+	}
+	if c.Layout.ListMinWidth != nil {
+		layout.ListMinWidth = *c.Layout.ListMinWidth
+	}
+	return layout
+}
+
+// UIKeyMap is nil when no keys are configured.
+func (c Config) UIKeyMap() *ui.KeyMap {
+	return c.keyMap
+}
+
+func (c Config) UITheme() map[string]ui.StyleOverride {
+	return c.theme
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + path[1:]
+}
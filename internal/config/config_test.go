@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	for _, path := range []string{"", filepath.Join(t.TempDir(), "missing.yaml")} {
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%q): %v", path, err)
+		}
+		if cfg.Path() != "" || cfg.PageSize != 0 || cfg.UIKeyMap() != nil {
+			t.Errorf("Load(%q) = %+v, want the zero Config", path, cfg)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		check   func(t *testing.T, cfg Config)
+	}{
+		{"empty", "", func(t *testing.T, cfg Config) {
+			if cfg.InactiveAfter != nil || cfg.Sort != "" {
+				t.Errorf("cfg = %+v, want defaults", cfg)
+			}
+		}},
+		{"settings", "page_size: 50\nsort: stars\nsync_interval: 1h\ninactive_after: 720h\nclone:\n  protocol: ssh\n", func(t *testing.T, cfg Config) {
+			if cfg.PageSize != 50 || cfg.Sort != "stars" || cfg.Clone.Protocol != "ssh" {
+				t.Errorf("cfg = %+v", cfg)
+			}
+			if cfg.SyncInterval == nil || *cfg.SyncInterval != time.Hour {
+				t.Errorf("sync_interval = %v, want 1h", cfg.SyncInterval)
+			}
+			if cfg.InactiveAfter == nil || *cfg.InactiveAfter != 720*time.Hour {
+				t.Errorf("inactive_after = %v, want 720h", cfg.InactiveAfter)
+			}
+		}},
+		{"layout", "layout:\n  list_ratio: 0.4\n", func(t *testing.T, cfg Config) {
+			layout := cfg.UILayout()
+			if layout.ListRatio != 0.4 || layout.ListMinWidth != 30 {
+				t.Errorf("layout = %+v, want ratio 0.4 and the default min width", layout)
+			}
+		}},
+		{"keys", "keys:\n  quit: [ctrl+q, Q]\n  search: ctrl+f\n", func(t *testing.T, cfg Config) {
+			keys := cfg.UIKeyMap()
+			if keys == nil {
+				t.Fatal("UIKeyMap() = nil, want the configured keys")
+			}
+			for key, want := range map[string]string{"ctrl+q": "quit", "Q": "quit", "ctrl+f": "search", "q": ""} {
+				if got := keys.Action(key); got != want {
+					t.Errorf("Action(%q) = %q, want %q", key, got, want)
+				}
+			}
+		}},
+		{"theme", "theme:\n  header_title:\n    foreground: \"#ff0000\"\n    bold: true\n", func(t *testing.T, cfg Config) {
+			if _, ok := cfg.UITheme()["header_title"]; !ok {
+				t.Errorf("theme = %v, want a header_title override", cfg.UITheme())
+			}
+		}},
+		{"home cache path", "cache: ~/stars.json\n", func(t *testing.T, cfg Config) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				t.Skip(err)
+			}
+			if want := filepath.Join(home, "stars.json"); cfg.Cache == nil || *cfg.Cache != want {
+				t.Errorf("cache = %v, want %s", cfg.Cache, want)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Path() != path {
+				t.Errorf("Path() = %q, want %q", cfg.Path(), path)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"unknown field", "page_sise: 10\n", []string{"field page_sise not found"}},
+		{"bad duration", "sync_interval: soon\n", []string{"cannot unmarshal !!str `soon` into time.Duration"}},
+		{"page size", "page_size: 101\n", []string{"page_size must be between 1 and 100"}},
+		{"negative interval", "sync_interval: -1h\nhydrate_interval: -1h\n", []string{"sync_interval must not be negative", "hydrate_interval must not be negative"}},
+		{"inactive after", "inactive_after: 0s\n", []string{"inactive_after must be positive"}},
+		{"sort", "sort: random\n", []string{`sort: unknown sort mode "random"`}},
+		{"protocol", "clone:\n  protocol: ftp\n", []string{"clone.protocol:"}},
+		{"layout", "layout:\n  list_ratio: 1.5\n", []string{"layout: list_ratio must be between 0 and 1"}},
+		{"keys", "keys:\n  launch: x\n", []string{`keys: unknown action "launch"`}},
+		{"key bound twice", "keys:\n  search: q\n", []string{`keys: key "q" is bound to both "quit" and "search"`}},
+		{"style", "theme:\n  sidebar:\n    bold: true\n", []string{`theme: unknown style "sidebar"`}},
+		{"color", "theme:\n  header_title:\n    foreground: red\n", []string{`theme.header_title: invalid color "red"`}},
+		{"adaptive color", "theme:\n  header_title:\n    foreground:\n      light: \"#fff\"\n", []string{"adaptive colors need both light and dark"}},
+		{"all at once", "page_size: -1\nsort: random\n", []string{"page_size", "sort:"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			_, err := Load(path)
+			if err == nil {
+				t.Fatal("Load succeeded, want an error")
+			}
+			if !strings.HasPrefix(err.Error(), path+":") {
+				t.Errorf("error %q does not name the file", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
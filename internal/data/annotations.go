@@ -0,0 +1,141 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Annotation is local-only metadata about a star. It lives outside Cache
+// because the cache can be rebuilt from GitHub at any time and notes cannot.
+type Annotation struct {
+	Note      string    `json:"note,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (a Annotation) IsEmpty() bool {
+	return strings.TrimSpace(a.Note) == "" && len(a.Tags) == 0
+}
+
+func (a Annotation) HasTag(tag string) bool {
+	for _, t := range a.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Annotations are keyed by NameWithOwner.
+type Annotations map[string]Annotation
+
+func AnnotationsPath(cachePath string) string {
+	if cachePath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(cachePath), "annotations.json")
+}
+
+func LoadAnnotations(path string) (Annotations, error) {
+	if path == "" {
+		return Annotations{}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Annotations{}, nil
+		}
+		return Annotations{}, err
+	}
+
+	annotations := Annotations{}
+	if err := json.Unmarshal(content, &annotations); err != nil {
+		return Annotations{}, err
+	}
+	return annotations, nil
+}
+
+// UpdateAnnotations applies update to the annotations saved at path and
+// returns what was written. The file is read again under its lock, so the
+// repos other instances annotated in the meantime are kept, and replaced
+// atomically, since notes cannot be fetched again if a write is torn.
+func UpdateAnnotations(path string, update func(Annotations)) (Annotations, error) {
+	if path == "" {
+		annotations := Annotations{}
+		update(annotations)
+		return annotations, nil
+	}
+
+	unlock, err := lockCache(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	annotations, err := LoadAnnotations(path)
+	if err != nil {
+		return nil, err
+	}
+	update(annotations)
+
+	content, err := json.MarshalIndent(annotations, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, content, 0o644); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// Set stores the annotation for name, dropping the entry once it has no
+// note or tags.
+func (a Annotations) Set(name string, annotation Annotation) {
+	if annotation.IsEmpty() {
+		delete(a, name)
+		return
+	}
+	annotation.UpdatedAt = time.Now().UTC()
+	a[name] = annotation
+}
+
+// Rename moves the annotation of a repo that was renamed or transferred.
+func (a Annotations) Rename(from, to string) {
+	annotation, ok := a[from]
+	if !ok {
+		return
+	}
+	delete(a, from)
+	if _, exists := a[to]; !exists {
+		a[to] = annotation
+	}
+}
+
+// ParseTags splits user input on commas and whitespace, dropping a leading
+// '#' and duplicates while keeping the first spelling of each tag.
+func ParseTags(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	tags := make([]string, 0, len(fields))
+	seen := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		tag := strings.TrimLeft(strings.TrimSpace(field), "#")
+		if tag == "" {
+			continue
+		}
+		key := strings.ToLower(tag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,86 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateAnnotationsKeepsOtherInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "annotations.json")
+
+	// Two instances loaded the same empty file and edit different repos.
+	if _, err := UpdateAnnotations(path, func(a Annotations) {
+		a.Set("a/1", Annotation{Note: "first"})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := UpdateAnnotations(path, func(a Annotations) {
+		a.Set("a/2", Annotation{Tags: []string{"cli"}})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved["a/1"].Note != "first" || !reflect.DeepEqual(saved["a/2"].Tags, []string{"cli"}) {
+		t.Errorf("saved = %+v, want both edits", saved)
+	}
+
+	// Editing one field keeps the other one saved by someone else.
+	if _, err := UpdateAnnotations(path, func(a Annotations) {
+		annotation := a["a/2"]
+		annotation.Note = "second"
+		a.Set("a/2", annotation)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadAnnotations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded["a/2"]; got.Note != "second" || !reflect.DeepEqual(got.Tags, []string{"cli"}) {
+		t.Errorf("a/2 = %+v, want note and tags", got)
+	}
+	if loaded["a/1"].Note != "first" {
+		t.Errorf("a/1 = %+v, want it kept", loaded["a/1"])
+	}
+}
+
+func TestUpdateAnnotationsKeepsDamagedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "annotations.json")
+	if err := os.WriteFile(path, []byte("{broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := UpdateAnnotations(path, func(a Annotations) {
+		a.Set("a/1", Annotation{Note: "lost?"})
+	}); err == nil {
+		t.Fatal("UpdateAnnotations succeeded on a damaged file")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "{broken" {
+		t.Errorf("damaged file was overwritten: %s", content)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{}},
+		{"cli, tui", []string{"cli", "tui"}},
+		{"#go  #Go go", []string{"go"}},
+		{"a,,b\tc\nd", []string{"a", "b", "c", "d"}},
+		{"# , #", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTags(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
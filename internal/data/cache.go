@@ -2,118 +2,220 @@ package data
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
-	"path/filepath"
 	"time"
-
-	gh "github.com/cli/go-gh/v2/pkg/api"
 )
 
 type Cache struct {
-	SavedAt time.Time `json:"saved_at"`
-	Repos   []Repo    `json:"repos"`
+	Version    int        `json:"version"`
+	SavedAt    time.Time  `json:"saved_at"`
+	HydratedAt time.Time  `json:"hydrated_at,omitempty"`
+	Repos      []Repo     `json:"repos"`
+	Lists      []StarList `json:"lists,omitempty"`
 }
 
+// LoadCache reads the cache at path, upgrading older schemas and falling
+// back to its backup when the file is unreadable. A missing cache is empty.
 func LoadCache(path string) (Cache, error) {
 	if path == "" {
 		return Cache{}, nil
 	}
 
-	content, err := os.ReadFile(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return Cache{}, nil
+	cache, err := readCache(path)
+	var versionErr *CacheVersionError
+	if errors.As(err, &versionErr) {
+		return Cache{}, err
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		if backup, backupErr := readCache(BackupPath(path)); backupErr == nil {
+			return backup, nil
 		}
 		return Cache{}, err
 	}
+	return cache, nil
+}
 
-	var file Cache
-	if err := json.Unmarshal(content, &file); err != nil {
+func readCache(path string) (Cache, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
 		return Cache{}, err
 	}
-	return file, nil
+
+	return decodeCache(path, content)
 }
 
-func SaveCache(path string, repos []Repo) error {
+// SaveCache replaces the cache at path. Use UpdateCache to keep what other
+// instances saved in the meantime.
+func SaveCache(path string, cache Cache) error {
 	if path == "" {
 		return nil
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return err
-	}
-
-	payload := Cache{
-		SavedAt: time.Now().UTC(),
-		Repos:   repos,
-	}
-
-	content, err := json.MarshalIndent(payload, "", "  ")
+	unlock, err := lockCache(path)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	return os.WriteFile(path, content, 0o644)
+	_, err = writeCache(path, cache)
+	return err
 }
 
 func IsStale(cache Cache, interval time.Duration) bool {
+	return isDue(cache.SavedAt, interval)
+}
+
+func NeedsHydration(cache Cache, interval time.Duration) bool {
+	return isDue(cache.HydratedAt, interval)
+}
+
+func isDue(last time.Time, interval time.Duration) bool {
 	if interval <= 0 {
 		return false
 	}
-	if cache.SavedAt.IsZero() {
+	if last.IsZero() {
 		return true
 	}
-	return time.Since(cache.SavedAt) >= interval
+	return time.Since(last) >= interval
 }
 
-func RefreshCache(client *gh.GraphQLClient, pageSize int, path string, cache Cache) error {
-	if path == "" || client == nil {
+// RefreshCache prepends stars newer than the cache and saves it. A cancelled
+// refresh returns ctx's error and leaves the cache file untouched.
+func RefreshCache(ctx context.Context, source StarSource, pageSize int, path string, cache Cache) error {
+	if path == "" || source == nil {
 		return nil
 	}
 
-	cacheIndex := make(map[string]struct{}, len(cache.Repos))
-	for _, repo := range cache.Repos {
+	added, err := FetchNewStars(ctx, source, pageSize, cache.Repos)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	updated := cache
+	updated.Repos = append(added, cache.Repos...)
+	_, _, err = UpdateCache(path, cache, updated)
+	return err
+}
+
+// FetchNewStars returns the stars missing from cached, newest first. Stars
+// are listed newest first, so it stops at the first page that reaches a
+// cached repo.
+func FetchNewStars(ctx context.Context, source StarSource, pageSize int, cached []Repo) ([]Repo, error) {
+	cacheIndex := make(map[string]struct{}, len(cached))
+	for _, repo := range cached {
 		cacheIndex[repo.NameWithOwner] = struct{}{}
 	}
 
-	repos := cache.Repos
+	added := []Repo{}
 	var cursor *string
+	var limit RateLimit
 
 	for {
-		page, err := FetchStarsPage(context.Background(), client, pageSize, cursor)
+		page, err := fetchPage(ctx, source, pageSize, cursor, limit)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		limit = page.RateLimit
 
-		newRepos := make([]Repo, 0, len(page.Repos))
 		foundCached := false
 		for _, repo := range page.Repos {
 			if _, exists := cacheIndex[repo.NameWithOwner]; exists {
 				foundCached = true
 				continue
 			}
-			newRepos = append(newRepos, repo)
+			added = append(added, repo)
 			cacheIndex[repo.NameWithOwner] = struct{}{}
 		}
 
-		if len(newRepos) > 0 {
-			repos = append(newRepos, repos...)
+		if foundCached || !page.HasNext || page.EndCursor == "" {
+			return added, nil
 		}
+		next := page.EndCursor
+		cursor = &next
+	}
+}
+
+type Rename struct {
+	From string
+	To   string
+}
 
-		if foundCached || !page.HasNext {
-			break
+type SyncResult struct {
+	Repos   []Repo
+	Added   []Repo
+	Removed []Repo
+	Renamed []Rename
+}
+
+func (r SyncResult) Summary() string {
+	summary := fmt.Sprintf("%d added, %d removed", len(r.Added), len(r.Removed))
+	if len(r.Renamed) > 0 {
+		summary += fmt.Sprintf(", %d renamed", len(r.Renamed))
+	}
+	return summary
+}
+
+// Reconcile compares a complete listing of starred repos against the cache.
+// Repos are matched by node ID first so renames and transfers are kept as
+// the same star, falling back to the name for entries cached without an ID.
+// Cached repos missing from the listing were unstarred or deleted upstream.
+func Reconcile(cached, fetched []Repo) SyncResult {
+	byID := make(map[string]int, len(cached))
+	byName := make(map[string]int, len(cached))
+	for i, repo := range cached {
+		if repo.ID != "" {
+			byID[repo.ID] = i
 		}
+		byName[repo.NameWithOwner] = i
+	}
 
-		if page.EndCursor != "" {
-			next := page.EndCursor
-			cursor = &next
-		} else {
-			break
+	seen := make(map[int]struct{}, len(cached))
+	result := SyncResult{Repos: fetched}
+	for _, repo := range fetched {
+		i, ok := byID[repo.ID]
+		if !ok || repo.ID == "" {
+			i, ok = byName[repo.NameWithOwner]
+		}
+		if !ok {
+			result.Added = append(result.Added, repo)
+			continue
+		}
+		seen[i] = struct{}{}
+		if previous := cached[i].NameWithOwner; previous != repo.NameWithOwner {
+			result.Renamed = append(result.Renamed, Rename{From: previous, To: repo.NameWithOwner})
 		}
 	}
 
-	return SaveCache(path, repos)
+	for i, repo := range cached {
+		if _, ok := seen[i]; !ok {
+			result.Removed = append(result.Removed, repo)
+		}
+	}
+
+	return result
+}
+
+func FetchAllStars(ctx context.Context, source StarSource, pageSize int) ([]Repo, error) {
+	repos := []Repo{}
+	var cursor *string
+	var limit RateLimit
+
+	for {
+		page, err := fetchPage(ctx, source, pageSize, cursor, limit)
+		if err != nil {
+			return nil, err
+		}
+		limit = page.RateLimit
+		repos = append(repos, page.Repos...)
+
+		if !page.HasNext || page.EndCursor == "" {
+			return repos, nil
+		}
+		next := page.EndCursor
+		cursor = &next
+	}
 }
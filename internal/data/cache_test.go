@@ -0,0 +1,118 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		cached         []Repo
+		fetched        []Repo
+		added, removed []string
+		renamed        []Rename
+	}{
+		{
+			name:    "unchanged",
+			cached:  []Repo{{ID: "R1", NameWithOwner: "a/1"}, {ID: "R2", NameWithOwner: "a/2"}},
+			fetched: []Repo{{ID: "R1", NameWithOwner: "a/1"}, {ID: "R2", NameWithOwner: "a/2"}},
+		},
+		{
+			name:    "added and removed",
+			cached:  []Repo{{ID: "R1", NameWithOwner: "a/1"}, {ID: "R2", NameWithOwner: "a/2"}},
+			fetched: []Repo{{ID: "R3", NameWithOwner: "a/3"}, {ID: "R1", NameWithOwner: "a/1"}},
+			added:   []string{"a/3"},
+			removed: []string{"a/2"},
+		},
+		{
+			name:    "renamed",
+			cached:  []Repo{{ID: "R1", NameWithOwner: "a/old"}},
+			fetched: []Repo{{ID: "R1", NameWithOwner: "a/new"}},
+			renamed: []Rename{{From: "a/old", To: "a/new"}},
+		},
+		{
+			name:    "transferred",
+			cached:  []Repo{{ID: "R1", NameWithOwner: "a/tool"}},
+			fetched: []Repo{{ID: "R1", NameWithOwner: "b/tool"}},
+			renamed: []Rename{{From: "a/tool", To: "b/tool"}},
+		},
+		{
+			name:    "ID wins over name",
+			cached:  []Repo{{ID: "R1", NameWithOwner: "a/1"}, {ID: "R2", NameWithOwner: "a/2"}},
+			fetched: []Repo{{ID: "R2", NameWithOwner: "a/1"}},
+			removed: []string{"a/1"},
+			renamed: []Rename{{From: "a/2", To: "a/1"}},
+		},
+		{
+			name:    "name fallback for cached without ID",
+			cached:  []Repo{{NameWithOwner: "a/1"}, {NameWithOwner: "a/2"}},
+			fetched: []Repo{{ID: "R1", NameWithOwner: "a/1"}},
+			removed: []string{"a/2"},
+		},
+		{
+			name:    "name fallback for fetched without ID",
+			cached:  []Repo{{ID: "R1", NameWithOwner: "a/1"}},
+			fetched: []Repo{{NameWithOwner: "a/1"}},
+		},
+		{
+			name:    "renamed without cached ID",
+			cached:  []Repo{{NameWithOwner: "a/old"}},
+			fetched: []Repo{{ID: "R1", NameWithOwner: "a/new"}},
+			added:   []string{"a/new"},
+			removed: []string{"a/old"},
+		},
+		{
+			name:    "empty cache",
+			fetched: []Repo{{ID: "R1", NameWithOwner: "a/1"}},
+			added:   []string{"a/1"},
+		},
+		{
+			name:    "everything unstarred",
+			cached:  []Repo{{ID: "R1", NameWithOwner: "a/1"}},
+			removed: []string{"a/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Reconcile(tt.cached, tt.fetched)
+			if !reflect.DeepEqual(result.Repos, tt.fetched) {
+				t.Errorf("repos = %+v, want the fetched listing", result.Repos)
+			}
+			if got := names(result.Added); !reflect.DeepEqual(got, nonNil(tt.added)) {
+				t.Errorf("added = %v, want %v", got, tt.added)
+			}
+			if got := names(result.Removed); !reflect.DeepEqual(got, nonNil(tt.removed)) {
+				t.Errorf("removed = %v, want %v", got, tt.removed)
+			}
+			if !reflect.DeepEqual(result.Renamed, tt.renamed) {
+				t.Errorf("renamed = %+v, want %+v", result.Renamed, tt.renamed)
+			}
+		})
+	}
+}
+
+func TestSyncResultSummary(t *testing.T) {
+	tests := []struct {
+		result SyncResult
+		want   string
+	}{
+		{SyncResult{}, "0 added, 0 removed"},
+		{SyncResult{Added: repos("a/1", "a/2"), Removed: repos("a/3")}, "2 added, 1 removed"},
+		{SyncResult{Renamed: []Rename{{From: "a/old", To: "a/new"}}}, "0 added, 0 removed, 1 renamed"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.result.Summary(); got != tt.want {
+			t.Errorf("Summary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
@@ -0,0 +1,173 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// BackupPath is where the previous generation of the cache at path is kept.
+func BackupPath(path string) string {
+	return path + ".bak"
+}
+
+// UpdateCache saves cache, which was derived from base. When another
+// instance saved the file after base was read, its changes are merged in
+// first and merged is true. The returned cache is what was written and the
+// base for the next update.
+func UpdateCache(path string, base, cache Cache) (saved Cache, merged bool, err error) {
+	if path == "" {
+		return cache, false, nil
+	}
+
+	unlock, err := lockCache(path)
+	if err != nil {
+		return cache, false, err
+	}
+	defer unlock()
+
+	// Not LoadCache: merging against the backup of a damaged file would bring
+	// back or drop stars. A missing or unreadable cache is simply replaced.
+	current, err := readCache(path)
+	var versionErr *CacheVersionError
+	if errors.As(err, &versionErr) {
+		return cache, false, err
+	}
+	if err == nil && !current.SavedAt.Equal(base.SavedAt) {
+		cache = MergeCache(base, current, cache)
+		merged = true
+	}
+
+	saved, err = writeCache(path, cache)
+	return saved, merged, err
+}
+
+// MergeCache combines ours with the changes another instance saved as
+// theirs, both starting from base: stars they added are kept, stars they
+// dropped are removed, and everything else is taken from ours.
+func MergeCache(base, theirs, ours Cache) Cache {
+	inBase := repoNames(base.Repos)
+	inTheirs := repoNames(theirs.Repos)
+	inOurs := repoNames(ours.Repos)
+
+	repos := make([]Repo, 0, len(ours.Repos))
+	for _, repo := range theirs.Repos {
+		if !inBase[repo.NameWithOwner] && !inOurs[repo.NameWithOwner] {
+			repos = append(repos, repo)
+		}
+	}
+	for _, repo := range ours.Repos {
+		if inBase[repo.NameWithOwner] && !inTheirs[repo.NameWithOwner] {
+			continue
+		}
+		repos = append(repos, repo)
+	}
+
+	merged := ours
+	merged.Repos = repos
+	if theirs.HydratedAt.After(merged.HydratedAt) {
+		merged.HydratedAt = theirs.HydratedAt
+	}
+	if reflect.DeepEqual(ours.Lists, base.Lists) {
+		merged.Lists = theirs.Lists
+	}
+	return merged
+}
+
+func repoNames(repos []Repo) map[string]bool {
+	names := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		names[repo.NameWithOwner] = true
+	}
+	return names
+}
+
+// writeCache stamps and writes cache, keeping the file it replaces as the
+// backup. The caller holds the lock.
+func writeCache(path string, cache Cache) (Cache, error) {
+	cache.SavedAt = time.Now().UTC()
+	return cache, storeCache(path, cache)
+}
+
+// storeCache writes cache in the current schema as is.
+func storeCache(path string, cache Cache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	cache.Version = CacheVersion
+	content, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := backupCache(path); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, content, 0o644)
+}
+
+// backupCache copies the current cache to its backup path. A cache that no
+// longer parses would make a useless backup, so the previous one is kept.
+func backupCache(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !json.Valid(content) {
+		return nil
+	}
+	return writeFileAtomic(BackupPath(path), content, 0o644)
+}
+
+// writeFileAtomic writes content next to path and renames it into place, so
+// readers and crashes only ever see the old or the new file.
+func writeFileAtomic(path string, content []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lockCache takes an advisory lock shared by every gh-stars process using
+// the cache at path, and returns the function releasing it.
+func lockCache(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		_ = unlockFile(file)
+		file.Close()
+	}, nil
+}
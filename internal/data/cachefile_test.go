@@ -0,0 +1,196 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func repos(names ...string) []Repo {
+	list := make([]Repo, 0, len(names))
+	for _, name := range names {
+		list = append(list, Repo{NameWithOwner: name})
+	}
+	return list
+}
+
+func names(list []Repo) []string {
+	out := make([]string, 0, len(list))
+	for _, repo := range list {
+		out = append(out, repo.NameWithOwner)
+	}
+	return out
+}
+
+func TestMergeCacheRepos(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, theirs, ours []string
+		want               []string
+	}{
+		{"unchanged", []string{"a/1", "a/2"}, []string{"a/1", "a/2"}, []string{"a/1", "a/2"}, []string{"a/1", "a/2"}},
+		{"they added", []string{"a/1"}, []string{"a/new", "a/1"}, []string{"a/1"}, []string{"a/new", "a/1"}},
+		{"we added", []string{"a/1"}, []string{"a/1"}, []string{"a/new", "a/1"}, []string{"a/new", "a/1"}},
+		{"both added the same", []string{"a/1"}, []string{"a/new", "a/1"}, []string{"a/new", "a/1"}, []string{"a/new", "a/1"}},
+		{"both added different", []string{"a/1"}, []string{"a/theirs", "a/1"}, []string{"a/ours", "a/1"}, []string{"a/theirs", "a/ours", "a/1"}},
+		{"they removed", []string{"a/1", "a/2"}, []string{"a/1"}, []string{"a/1", "a/2"}, []string{"a/1"}},
+		{"we removed", []string{"a/1", "a/2"}, []string{"a/1", "a/2"}, []string{"a/1"}, []string{"a/1"}},
+		{"both removed", []string{"a/1", "a/2"}, []string{"a/1"}, []string{"a/1"}, []string{"a/1"}},
+		{"they removed, we added", []string{"a/1", "a/2"}, []string{"a/1"}, []string{"a/new", "a/1", "a/2"}, []string{"a/new", "a/1"}},
+		{"they added, we removed", []string{"a/1", "a/2"}, []string{"a/new", "a/1", "a/2"}, []string{"a/2"}, []string{"a/new", "a/2"}},
+		{"first save by them", nil, []string{"a/1"}, nil, []string{"a/1"}},
+		{"empty base", nil, []string{"a/1", "a/2"}, []string{"a/2", "a/3"}, []string{"a/1", "a/2", "a/3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeCache(Cache{Repos: repos(tt.base...)}, Cache{Repos: repos(tt.theirs...)}, Cache{Repos: repos(tt.ours...)})
+			if got := names(merged.Repos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("repos = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeCacheKeepsOurRepoData(t *testing.T) {
+	base := Cache{Repos: []Repo{{NameWithOwner: "a/1", Stars: 1}}}
+	theirs := Cache{Repos: []Repo{{NameWithOwner: "a/1", Stars: 2}}}
+	ours := Cache{Repos: []Repo{{NameWithOwner: "a/1", Stars: 3}}}
+
+	merged := MergeCache(base, theirs, ours)
+	if len(merged.Repos) != 1 || merged.Repos[0].Stars != 3 {
+		t.Errorf("repos = %+v, want ours", merged.Repos)
+	}
+}
+
+func TestMergeCacheLists(t *testing.T) {
+	baseLists := []StarList{{ID: "L1", Name: "tools", Repos: []string{"a/1"}}}
+	theirLists := []StarList{{ID: "L1", Name: "tools", Repos: []string{"a/1", "a/2"}}}
+	ourLists := []StarList{{ID: "L1", Name: "renamed", Repos: []string{"a/1"}}}
+
+	tests := []struct {
+		name               string
+		base, theirs, ours []StarList
+		want               []StarList
+	}{
+		{"only they changed", baseLists, theirLists, baseLists, theirLists},
+		{"only we changed", baseLists, baseLists, ourLists, ourLists},
+		{"both changed", baseLists, theirLists, ourLists, ourLists},
+		{"they cleared", baseLists, nil, baseLists, nil},
+		{"we cleared", baseLists, theirLists, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeCache(Cache{Lists: tt.base}, Cache{Lists: tt.theirs}, Cache{Lists: tt.ours})
+			if !reflect.DeepEqual(merged.Lists, tt.want) {
+				t.Errorf("lists = %+v, want %+v", merged.Lists, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeCacheHydratedAt(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		theirs, ours time.Time
+		want         time.Time
+	}{
+		{"theirs newer", newer, older, newer},
+		{"ours newer", older, newer, newer},
+		{"never hydrated", time.Time{}, time.Time{}, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeCache(Cache{}, Cache{HydratedAt: tt.theirs}, Cache{HydratedAt: tt.ours})
+			if !merged.HydratedAt.Equal(tt.want) {
+				t.Errorf("HydratedAt = %v, want %v", merged.HydratedAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateCacheMergesConcurrentSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	base, _, err := UpdateCache(path, Cache{}, Cache{Repos: repos("a/1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := UpdateCache(path, base, Cache{Repos: repos("a/theirs", "a/1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, merged, err := UpdateCache(path, base, Cache{Repos: repos("a/ours", "a/1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged {
+		t.Error("merged = false, want true")
+	}
+	want := []string{"a/theirs", "a/ours", "a/1"}
+	if got := names(saved.Repos); !reflect.DeepEqual(got, want) {
+		t.Errorf("saved repos = %v, want %v", got, want)
+	}
+	loaded, err := LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(loaded.Repos); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded repos = %v, want %v", got, want)
+	}
+}
+
+func TestUpdateCacheIgnoresBackupOfDamagedCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	base, _, err := UpdateCache(path, Cache{}, Cache{Repos: repos("a/1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := UpdateCache(path, base, Cache{Repos: repos("a/1", "a/2")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, merged, err := UpdateCache(path, base, Cache{Repos: repos("a/3")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged {
+		t.Error("merged against a damaged cache")
+	}
+	if got, want := names(saved.Repos), []string{"a/3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("saved repos = %v, want %v", got, want)
+	}
+}
+
+func TestUpdateCacheKeepsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	future := []byte(`{"version": 99, "repos": []}`)
+	if err := os.WriteFile(path, future, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := UpdateCache(path, Cache{}, Cache{Repos: repos("a/1")})
+	var versionErr *CacheVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("err = %v, want *CacheVersionError", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(future) {
+		t.Errorf("cache was overwritten: %s", content)
+	}
+}
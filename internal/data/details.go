@@ -0,0 +1,179 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// Details is metadata that is only shown in the preview, so it is fetched
+// one repo at a time instead of with every page of stars.
+type Details struct {
+	Repo          string    `json:"repo"`
+	License       string    `json:"license,omitempty"`
+	Homepage      string    `json:"homepage,omitempty"`
+	Forks         int       `json:"forks"`
+	Watchers      int       `json:"watchers"`
+	OpenIssues    int       `json:"open_issues"`
+	OpenPRs       int       `json:"open_prs"`
+	LatestRelease string    `json:"latest_release,omitempty"`
+	ReleasedAt    time.Time `json:"released_at,omitempty"`
+	DefaultBranch string    `json:"default_branch,omitempty"`
+	DiskUsageKB   int       `json:"disk_usage_kb,omitempty"`
+	Topics        []string  `json:"topics,omitempty"`
+	FetchedAt     time.Time `json:"fetched_at"`
+}
+
+func FetchDetails(ctx context.Context, client *gh.GraphQLClient, nameWithOwner string) (Details, error) {
+	if client == nil {
+		return Details{}, errors.New("nil GraphQL client")
+	}
+
+	owner, name, ok := strings.Cut(nameWithOwner, "/")
+	if !ok {
+		return Details{}, fmt.Errorf("invalid repository name %q", nameWithOwner)
+	}
+
+	select {
+	case <-ctx.Done():
+		return Details{}, ctx.Err()
+	default:
+	}
+
+	var query struct {
+		Repository *struct {
+			LicenseInfo *struct {
+				Name   string
+				SpdxID string `graphql:"spdxId"`
+			}
+			HomepageURL string `graphql:"homepageUrl"`
+			ForkCount   int
+			Watchers    struct {
+				TotalCount int
+			}
+			Issues struct {
+				TotalCount int
+			} `graphql:"issues(states: [OPEN])"`
+			PullRequests struct {
+				TotalCount int
+			} `graphql:"pullRequests(states: [OPEN])"`
+			LatestRelease *struct {
+				TagName     string
+				PublishedAt time.Time
+			}
+			DefaultBranchRef *struct {
+				Name string
+			}
+			DiskUsage        *int
+			RepositoryTopics struct {
+				Nodes []topicNode
+			} `graphql:"repositoryTopics(first: 100)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]any{
+		"owner": graphql.String(owner),
+		"name":  graphql.String(name),
+	}
+
+	if err := client.QueryWithContext(ctx, "RepositoryDetails", &query, variables); err != nil {
+		return Details{}, err
+	}
+	repo := query.Repository
+	if repo == nil {
+		return Details{}, fmt.Errorf("repository %s not found", nameWithOwner)
+	}
+
+	details := Details{
+		Repo:       nameWithOwner,
+		Homepage:   strings.TrimSpace(repo.HomepageURL),
+		Forks:      repo.ForkCount,
+		Watchers:   repo.Watchers.TotalCount,
+		OpenIssues: repo.Issues.TotalCount,
+		OpenPRs:    repo.PullRequests.TotalCount,
+		FetchedAt:  time.Now().UTC(),
+	}
+	if repo.LicenseInfo != nil {
+		// GitHub reports unrecognized licenses as NOASSERTION.
+		details.License = repo.LicenseInfo.SpdxID
+		if details.License == "" || details.License == "NOASSERTION" {
+			details.License = repo.LicenseInfo.Name
+		}
+	}
+	if repo.LatestRelease != nil {
+		details.LatestRelease = repo.LatestRelease.TagName
+		details.ReleasedAt = repo.LatestRelease.PublishedAt
+	}
+	if repo.DefaultBranchRef != nil {
+		details.DefaultBranch = repo.DefaultBranchRef.Name
+	}
+	if repo.DiskUsage != nil {
+		details.DiskUsageKB = *repo.DiskUsage
+	}
+	for _, topic := range repo.RepositoryTopics.Nodes {
+		if name := strings.TrimSpace(topic.Topic.Name); name != "" {
+			details.Topics = append(details.Topics, name)
+		}
+	}
+
+	return details, nil
+}
+
+func DetailsDir(cachePath string) string {
+	if cachePath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(cachePath), "details")
+}
+
+func LoadDetails(dir, nameWithOwner string) (Details, error) {
+	if dir == "" {
+		return Details{}, nil
+	}
+
+	content, err := os.ReadFile(repoFilePath(dir, nameWithOwner))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Details{}, nil
+		}
+		return Details{}, err
+	}
+
+	var details Details
+	if err := json.Unmarshal(content, &details); err != nil {
+		return Details{}, err
+	}
+	return details, nil
+}
+
+func SaveDetails(dir string, details Details) error {
+	if dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(repoFilePath(dir, details.Repo), content, 0o644)
+}
+
+func DetailsAreStale(details Details, ttl time.Duration) bool {
+	if details.FetchedAt.IsZero() {
+		return true
+	}
+	return ttl > 0 && time.Since(details.FetchedAt) >= ttl
+}
@@ -0,0 +1,49 @@
+package data
+
+import "time"
+
+// DefaultInactiveAfter is how long a repo can go without a push before it
+// counts as inactive, unless the inactive_after setting says otherwise.
+const DefaultInactiveAfter = 365 * 24 * time.Hour
+
+// Health ranks how likely a star is to be dead weight. Higher is worse.
+type Health int
+
+const (
+	Healthy Health = iota
+	Inactive
+	Archived
+	Disabled
+)
+
+func (h Health) String() string {
+	switch h {
+	case Inactive:
+		return "inactive"
+	case Archived:
+		return "archived"
+	case Disabled:
+		return "disabled"
+	}
+	return "healthy"
+}
+
+// Health reports the worst condition that applies to r.
+func (r Repo) Health(now time.Time, inactiveAfter time.Duration) Health {
+	switch {
+	case r.IsDisabled:
+		return Disabled
+	case r.IsArchived:
+		return Archived
+	case r.IsInactive(now, inactiveAfter):
+		return Inactive
+	}
+	return Healthy
+}
+
+// IsInactive reports whether r went without a push for longer than after.
+// Repos whose last push is unknown (caches written before it was fetched)
+// are never inactive.
+func (r Repo) IsInactive(now time.Time, after time.Duration) bool {
+	return !r.PushedAt.IsZero() && now.Sub(r.PushedAt) > after
+}
@@ -0,0 +1,140 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// MaxNodesPerQuery is the most IDs GitHub accepts in a single nodes() lookup.
+const MaxNodesPerQuery = 100
+
+// FetchReposByID re-queries repositories by node ID in batches and returns
+// the current metadata keyed by ID. Repos that no longer resolve (deleted or
+// inaccessible) are absent from the result.
+func FetchReposByID(ctx context.Context, client *gh.GraphQLClient, ids []string) (map[string]Repo, error) {
+	if client == nil {
+		return nil, errors.New("nil GraphQL client")
+	}
+
+	repos := make(map[string]Repo, len(ids))
+	for start := 0; start < len(ids); start += MaxNodesPerQuery {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		end := min(start+MaxNodesPerQuery, len(ids))
+		batch := make([]graphql.ID, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = append(batch, graphql.ID(id))
+		}
+
+		var query struct {
+			Nodes []struct {
+				Repository repoNode `graphql:"... on Repository"`
+			} `graphql:"nodes(ids: $ids)"`
+		}
+
+		variables := map[string]any{
+			"ids": batch,
+		}
+
+		if err := client.QueryWithContext(ctx, "RepositoriesByID", &query, variables); err != nil && !onlyNotFound(err) {
+			return nil, err
+		}
+
+		for _, node := range query.Nodes {
+			if node.Repository.ID == "" {
+				continue
+			}
+			repos[node.Repository.ID] = node.Repository.toRepo(time.Time{})
+		}
+	}
+
+	return repos, nil
+}
+
+// onlyNotFound reports whether err only lists IDs that no longer resolve.
+// GitHub still returns the other nodes of the batch alongside them.
+func onlyNotFound(err error) bool {
+	var gqlErr *gh.GraphQLError
+	if !errors.As(err, &gqlErr) || len(gqlErr.Errors) == 0 {
+		return false
+	}
+	for _, item := range gqlErr.Errors {
+		if item.Type != "NOT_FOUND" {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyMetadata overwrites repos with fresh metadata by ID, keeping the
+// locally known star date. It returns the updated slice and how many entries
+// changed.
+func ApplyMetadata(repos []Repo, fresh map[string]Repo) ([]Repo, int) {
+	updated := make([]Repo, len(repos))
+	changed := 0
+	for i, repo := range repos {
+		updated[i] = repo
+		next, ok := fresh[repo.ID]
+		if repo.ID == "" || !ok {
+			continue
+		}
+		next.StarredAt = repo.StarredAt
+		if !sameRepo(repo, next) {
+			changed++
+		}
+		updated[i] = next
+	}
+	return updated, changed
+}
+
+// MissingIDs reports whether any repo was cached before node IDs were
+// stored, so it cannot be hydrated by ID.
+func MissingIDs(repos []Repo) bool {
+	for _, repo := range repos {
+		if repo.ID == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func RepoIDs(repos []Repo) []string {
+	ids := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		if repo.ID != "" {
+			ids = append(ids, repo.ID)
+		}
+	}
+	return ids
+}
+
+func sameRepo(a, b Repo) bool {
+	if a.NameWithOwner != b.NameWithOwner ||
+		a.Description != b.Description ||
+		a.URL != b.URL ||
+		a.Stars != b.Stars ||
+		a.PrimaryLanguage != b.PrimaryLanguage ||
+		a.IsFork != b.IsFork ||
+		a.IsArchived != b.IsArchived ||
+		a.IsDisabled != b.IsDisabled ||
+		a.IsMirror != b.IsMirror ||
+		!a.UpdatedAt.Equal(b.UpdatedAt) ||
+		!a.PushedAt.Equal(b.PushedAt) ||
+		len(a.Topics) != len(b.Topics) {
+		return false
+	}
+	for i := range a.Topics {
+		if a.Topics[i] != b.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package data
+
+import (
+	"context"
+	"errors"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+type StarList struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Slug        string   `json:"slug"`
+	Private     bool     `json:"private,omitempty"`
+	Repos       []string `json:"repos"`
+}
+
+func (l StarList) Contains(nameWithOwner string) bool {
+	for _, name := range l.Repos {
+		if name == nameWithOwner {
+			return true
+		}
+	}
+	return false
+}
+
+type listItems struct {
+	Nodes []struct {
+		Repository struct {
+			NameWithOwner string `graphql:"nameWithOwner"`
+		} `graphql:"... on Repository"`
+	}
+	PageInfo struct {
+		HasNextPage bool
+		EndCursor   graphql.String
+	}
+}
+
+type UpdateUserListsForItemInput struct {
+	ItemID  graphql.ID   `json:"itemId"`
+	ListIDs []graphql.ID `json:"listIds"`
+}
+
+func FetchStarLists(ctx context.Context, client *gh.GraphQLClient) ([]StarList, error) {
+	if client == nil {
+		return nil, errors.New("nil GraphQL client")
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	var query struct {
+		Viewer struct {
+			Lists struct {
+				Nodes []struct {
+					ID          string `graphql:"id"`
+					Name        string
+					Description string
+					Slug        string
+					IsPrivate   bool      `graphql:"isPrivate"`
+					Items       listItems `graphql:"items(first: 100)"`
+				}
+			} `graphql:"lists(first: 100)"`
+		}
+	}
+
+	if err := client.QueryWithContext(ctx, "ViewerStarLists", &query, nil); err != nil {
+		return nil, err
+	}
+
+	lists := make([]StarList, 0, len(query.Viewer.Lists.Nodes))
+	for _, node := range query.Viewer.Lists.Nodes {
+		list := StarList{
+			ID:          node.ID,
+			Name:        node.Name,
+			Description: node.Description,
+			Slug:        node.Slug,
+			Private:     node.IsPrivate,
+			Repos:       itemNames(node.Items),
+		}
+
+		items := node.Items
+		for items.PageInfo.HasNextPage && items.PageInfo.EndCursor != "" {
+			next, err := fetchListItems(ctx, client, node.ID, string(items.PageInfo.EndCursor))
+			if err != nil {
+				return nil, err
+			}
+			list.Repos = append(list.Repos, itemNames(next)...)
+			items = next
+		}
+
+		lists = append(lists, list)
+	}
+
+	return lists, nil
+}
+
+func fetchListItems(ctx context.Context, client *gh.GraphQLClient, listID, after string) (listItems, error) {
+	select {
+	case <-ctx.Done():
+		return listItems{}, ctx.Err()
+	default:
+	}
+
+	var query struct {
+		Node struct {
+			UserList struct {
+				Items listItems `graphql:"items(first: 100, after: $after)"`
+			} `graphql:"... on UserList"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]any{
+		"id":    graphql.ID(listID),
+		"after": graphql.String(after),
+	}
+
+	if err := client.QueryWithContext(ctx, "StarListItems", &query, variables); err != nil {
+		return listItems{}, err
+	}
+	return query.Node.UserList.Items, nil
+}
+
+func itemNames(items listItems) []string {
+	names := make([]string, 0, len(items.Nodes))
+	for _, node := range items.Nodes {
+		if node.Repository.NameWithOwner != "" {
+			names = append(names, node.Repository.NameWithOwner)
+		}
+	}
+	return names
+}
+
+// SetRepoLists replaces the set of lists repo belongs to. An empty listIDs
+// removes it from every list (but keeps the star).
+func SetRepoLists(ctx context.Context, client *gh.GraphQLClient, repo Repo, listIDs []string) error {
+	id, err := repoNodeID(ctx, client, repo)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]graphql.ID, 0, len(listIDs))
+	for _, listID := range listIDs {
+		ids = append(ids, graphql.ID(listID))
+	}
+
+	var mutation struct {
+		UpdateUserListsForItem struct {
+			Lists []struct {
+				ID string `graphql:"id"`
+			}
+		} `graphql:"updateUserListsForItem(input: $input)"`
+	}
+
+	variables := map[string]any{
+		"input": UpdateUserListsForItemInput{ItemID: graphql.ID(id), ListIDs: ids},
+	}
+
+	return client.MutateWithContext(ctx, "UpdateUserListsForItem", &mutation, variables)
+}
+
+// WithRepoLists returns a copy of lists with repo's membership set to
+// exactly listIDs.
+func WithRepoLists(lists []StarList, nameWithOwner string, listIDs []string) []StarList {
+	want := make(map[string]struct{}, len(listIDs))
+	for _, id := range listIDs {
+		want[id] = struct{}{}
+	}
+
+	updated := make([]StarList, len(lists))
+	for i, list := range lists {
+		repos := make([]string, 0, len(list.Repos)+1)
+		for _, name := range list.Repos {
+			if name != nameWithOwner {
+				repos = append(repos, name)
+			}
+		}
+		if _, ok := want[list.ID]; ok {
+			repos = append(repos, nameWithOwner)
+		}
+		list.Repos = repos
+		updated[i] = list
+	}
+	return updated
+}
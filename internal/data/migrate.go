@@ -0,0 +1,153 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CacheVersion is the cache schema this build reads and writes. Bump it
+// together with a migration in cacheMigrations whenever cached data needs
+// more than zero values for new Repo fields.
+const CacheVersion = 1
+
+// LegacyCachePath is where caches were kept, relative to the working
+// directory, before they moved next to the config file.
+const LegacyCachePath = ".cache/gh-stars.json"
+
+// cacheMigration upgrades a decoded cache file by one version in place.
+type cacheMigration func(cache map[string]any) error
+
+// cacheMigrations holds, for every version below CacheVersion, the step to
+// the next one. Files written before versioning are version 0.
+var cacheMigrations = map[int]cacheMigration{
+	0: refetchHealth,
+}
+
+// CacheVersionError reports a cache written by a newer gh-stars. It is never
+// overwritten, since this build would drop whatever the newer one added.
+type CacheVersionError struct {
+	Path    string
+	Version int
+}
+
+func (e *CacheVersionError) Error() string {
+	return fmt.Sprintf("cache %s has schema version %d but this gh-stars only reads up to %d; upgrade gh-stars or point -cache elsewhere", e.Path, e.Version, CacheVersion)
+}
+
+// UpgradeCache rewrites the cache at path in the current schema. When path
+// has no cache yet, one found at legacyPath is moved over; pass "" to skip
+// that. The save date is kept, so the upgrade does not delay the next sync.
+func UpgradeCache(path, legacyPath string) error {
+	if path == "" {
+		return nil
+	}
+
+	unlock, err := lockCache(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	from := path
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && legacyPath != "" {
+		from = legacyPath
+		content, err = os.ReadFile(legacyPath)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	version, err := cacheVersion(content)
+	if err != nil {
+		return err
+	}
+	if from == path && version == CacheVersion {
+		return nil
+	}
+	cache, err := decodeCache(from, content)
+	if err != nil {
+		return err
+	}
+	if from != path && len(cache.Repos) == 0 {
+		return nil
+	}
+	return storeCache(path, cache)
+}
+
+func cacheVersion(content []byte) (int, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	err := json.Unmarshal(content, &header)
+	return header.Version, err
+}
+
+// decodeCache parses a cache file, migrating older schemas first.
+func decodeCache(path string, content []byte) (Cache, error) {
+	version, err := cacheVersion(content)
+	if err != nil {
+		return Cache{}, err
+	}
+	if version > CacheVersion {
+		return Cache{}, &CacheVersionError{Path: path, Version: version}
+	}
+	if version < CacheVersion {
+		migrated, err := migrateCache(content, version)
+		if err != nil {
+			return Cache{}, fmt.Errorf("migrate cache %s: %w", path, err)
+		}
+		content = migrated
+	}
+
+	var cache Cache
+	if err := json.Unmarshal(content, &cache); err != nil {
+		return Cache{}, err
+	}
+	return cache, nil
+}
+
+func migrateCache(content []byte, version int) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var cache map[string]any
+	if err := decoder.Decode(&cache); err != nil {
+		return nil, err
+	}
+
+	for ; version < CacheVersion; version++ {
+		migrate, ok := cacheMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration from version %d", version)
+		}
+		if err := migrate(cache); err != nil {
+			return nil, fmt.Errorf("version %d: %w", version, err)
+		}
+	}
+	cache["version"] = CacheVersion
+	return json.Marshal(cache)
+}
+
+// refetchHealth migrates to version 1, which added push dates and archive
+// state. Caches with repos lacking either those or a node ID are refreshed
+// right away instead of on the weekly schedule; the UI runs a full sync to
+// match repos without an ID.
+func refetchHealth(cache map[string]any) error {
+	repos, _ := cache["repos"].([]any)
+	for _, entry := range repos {
+		repo, _ := entry.(map[string]any)
+		id, _ := repo["ID"].(string)
+		pushed, _ := repo["PushedAt"].(string)
+		if id == "" || pushed == "" || pushed == "0001-01-01T00:00:00Z" {
+			delete(cache, "hydrated_at")
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeCache(t *testing.T) {
+	hydrated := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	pushed := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		content string
+		want    Cache
+	}{
+		{
+			name:    "current",
+			content: `{"version": 1, "hydrated_at": "2024-03-01T00:00:00Z", "repos": [{"ID": "R1", "NameWithOwner": "a/1"}]}`,
+			want:    Cache{Version: 1, HydratedAt: hydrated, Repos: []Repo{{ID: "R1", NameWithOwner: "a/1"}}},
+		},
+		{
+			name:    "v0 complete",
+			content: `{"hydrated_at": "2024-03-01T00:00:00Z", "repos": [{"ID": "R1", "NameWithOwner": "a/1", "PushedAt": "2024-02-01T00:00:00Z"}]}`,
+			want:    Cache{Version: 1, HydratedAt: hydrated, Repos: []Repo{{ID: "R1", NameWithOwner: "a/1", PushedAt: pushed}}},
+		},
+		{
+			name:    "v0 without push dates",
+			content: `{"hydrated_at": "2024-03-01T00:00:00Z", "repos": [{"ID": "R1", "NameWithOwner": "a/1"}]}`,
+			want:    Cache{Version: 1, Repos: []Repo{{ID: "R1", NameWithOwner: "a/1"}}},
+		},
+		{
+			name:    "v0 with zero push date",
+			content: `{"version": 0, "hydrated_at": "2024-03-01T00:00:00Z", "repos": [{"ID": "R1", "NameWithOwner": "a/1", "PushedAt": "0001-01-01T00:00:00Z"}]}`,
+			want:    Cache{Version: 1, Repos: []Repo{{ID: "R1", NameWithOwner: "a/1"}}},
+		},
+		{
+			name:    "v0 without IDs",
+			content: `{"hydrated_at": "2024-03-01T00:00:00Z", "repos": [{"NameWithOwner": "a/1", "PushedAt": "2024-02-01T00:00:00Z"}]}`,
+			want:    Cache{Version: 1, Repos: []Repo{{NameWithOwner: "a/1", PushedAt: pushed}}},
+		},
+		{
+			name:    "v0 empty",
+			content: `{"repos": []}`,
+			want:    Cache{Version: 1, Repos: []Repo{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCache("cache.json", []byte(tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCache = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCacheFutureVersion(t *testing.T) {
+	_, err := decodeCache("cache.json", []byte(`{"version": 2, "repos": []}`))
+	var versionErr *CacheVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("err = %v, want *CacheVersionError", err)
+	}
+	if versionErr.Path != "cache.json" || versionErr.Version != 2 {
+		t.Errorf("err = %+v", versionErr)
+	}
+}
+
+func TestLoadCache(t *testing.T) {
+	good := `{"version": 1, "repos": [{"NameWithOwner": "a/backup"}]}`
+
+	tests := []struct {
+		name          string
+		cache, backup string
+		want          []string
+		wantErr       bool
+	}{
+		{name: "missing", want: []string{}},
+		{name: "corrupt falls back to backup", cache: `{"repos": [`, backup: good, want: []string{"a/backup"}},
+		{name: "corrupt without backup", cache: `{"repos": [`, wantErr: true},
+		{name: "corrupt with corrupt backup", cache: `{"repos": [`, backup: `nope`, wantErr: true},
+		{name: "future version ignores backup", cache: `{"version": 9, "repos": []}`, backup: good, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.json")
+			if tt.cache != "" {
+				if err := os.WriteFile(path, []byte(tt.cache), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.backup != "" {
+				if err := os.WriteFile(BackupPath(path), []byte(tt.backup), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cache, err := LoadCache(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LoadCache succeeded with %+v", cache)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(cache.Repos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("repos = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache.json")
+	legacy := filepath.Join(dir, "legacy.json")
+	v0 := `{"saved_at": "2024-03-01T00:00:00Z", "repos": [{"ID": "R1", "NameWithOwner": "a/1"}]}`
+	if err := os.WriteFile(legacy, []byte(v0), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpgradeCache(path, legacy); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := cacheVersion(content); err != nil || version != CacheVersion {
+		t.Errorf("version = %d, %v; want %d", version, err, CacheVersion)
+	}
+	cache, err := LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(cache.Repos), []string{"a/1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("repos = %v, want %v", got, want)
+	}
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !cache.SavedAt.Equal(want) {
+		t.Errorf("SavedAt = %v, want %v", cache.SavedAt, want)
+	}
+
+	// A cache at path wins over the legacy one from now on.
+	if err := os.WriteFile(legacy, []byte(`{"repos": [{"NameWithOwner": "a/legacy"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpgradeCache(path, legacy); err != nil {
+		t.Fatal(err)
+	}
+	cache, err = LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(cache.Repos), []string{"a/1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("repos after second upgrade = %v, want %v", got, want)
+	}
+}
+
+func TestUpgradeCacheSkipsEmptyLegacy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache.json")
+	legacy := filepath.Join(dir, "legacy.json")
+	if err := os.WriteFile(legacy, []byte(`{"repos": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpgradeCache(path, legacy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stat %s: %v, want no cache", path, err)
+	}
+}
@@ -0,0 +1,106 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// The input type names must match the GraphQL schema, since the client
+// derives variable types from the Go type name.
+
+type AddStarInput struct {
+	StarrableID graphql.ID `json:"starrableId"`
+}
+
+type RemoveStarInput struct {
+	StarrableID graphql.ID `json:"starrableId"`
+}
+
+func AddStar(ctx context.Context, client *gh.GraphQLClient, repo Repo) error {
+	id, err := repoNodeID(ctx, client, repo)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		AddStar struct {
+			Starrable struct {
+				ViewerHasStarred bool
+			}
+		} `graphql:"addStar(input: $input)"`
+	}
+
+	variables := map[string]any{
+		"input": AddStarInput{StarrableID: graphql.ID(id)},
+	}
+
+	return client.MutateWithContext(ctx, "AddStar", &mutation, variables)
+}
+
+func RemoveStar(ctx context.Context, client *gh.GraphQLClient, repo Repo) error {
+	id, err := repoNodeID(ctx, client, repo)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		RemoveStar struct {
+			Starrable struct {
+				ViewerHasStarred bool
+			}
+		} `graphql:"removeStar(input: $input)"`
+	}
+
+	variables := map[string]any{
+		"input": RemoveStarInput{StarrableID: graphql.ID(id)},
+	}
+
+	return client.MutateWithContext(ctx, "RemoveStar", &mutation, variables)
+}
+
+// repoNodeID returns the repo's node ID, looking it up by name for entries
+// cached before IDs were stored.
+func repoNodeID(ctx context.Context, client *gh.GraphQLClient, repo Repo) (string, error) {
+	if client == nil {
+		return "", errors.New("nil GraphQL client")
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	if repo.ID != "" {
+		return repo.ID, nil
+	}
+
+	owner, name, ok := strings.Cut(repo.NameWithOwner, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid repository name %q", repo.NameWithOwner)
+	}
+
+	var query struct {
+		Repository *struct {
+			ID string `graphql:"id"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]any{
+		"owner": graphql.String(owner),
+		"name":  graphql.String(name),
+	}
+
+	if err := client.QueryWithContext(ctx, "RepositoryID", &query, variables); err != nil {
+		return "", err
+	}
+	if query.Repository == nil {
+		return "", fmt.Errorf("repository %s not found", repo.NameWithOwner)
+	}
+	return query.Repository.ID, nil
+}
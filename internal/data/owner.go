@@ -0,0 +1,64 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// Owner is a user or organization login. Organizations cannot star repos,
+// so for them Members lists the (public) members whose stars can be browsed.
+type Owner struct {
+	Login          string
+	IsOrganization bool
+	Members        []string
+}
+
+func FetchOwner(ctx context.Context, client *gh.GraphQLClient, login string) (Owner, error) {
+	if client == nil {
+		return Owner{}, errors.New("nil GraphQL client")
+	}
+
+	select {
+	case <-ctx.Done():
+		return Owner{}, ctx.Err()
+	default:
+	}
+
+	var query struct {
+		RepositoryOwner *struct {
+			Typename     string `graphql:"__typename"`
+			Login        string
+			Organization struct {
+				MembersWithRole struct {
+					Nodes []struct {
+						Login string
+					}
+				} `graphql:"membersWithRole(first: 100)"`
+			} `graphql:"... on Organization"`
+		} `graphql:"repositoryOwner(login: $login)"`
+	}
+
+	variables := map[string]any{
+		"login": graphql.String(login),
+	}
+
+	if err := client.QueryWithContext(ctx, "RepositoryOwner", &query, variables); err != nil {
+		return Owner{}, err
+	}
+	if query.RepositoryOwner == nil {
+		return Owner{}, fmt.Errorf("no user or organization named %s", login)
+	}
+
+	owner := Owner{
+		Login:          query.RepositoryOwner.Login,
+		IsOrganization: query.RepositoryOwner.Typename == "Organization",
+	}
+	for _, member := range query.RepositoryOwner.Organization.MembersWithRole.Nodes {
+		owner.Members = append(owner.Members, member.Login)
+	}
+	return owner, nil
+}
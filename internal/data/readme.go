@@ -0,0 +1,141 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+type Readme struct {
+	Repo      string    `json:"repo"`
+	Text      string    `json:"text"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+type readmeObject struct {
+	Blob struct {
+		Text     string
+		IsBinary bool `graphql:"isBinary"`
+	} `graphql:"... on Blob"`
+}
+
+func FetchReadme(ctx context.Context, client *gh.GraphQLClient, nameWithOwner string) (Readme, error) {
+	if client == nil {
+		return Readme{}, errors.New("nil GraphQL client")
+	}
+
+	owner, name, ok := strings.Cut(nameWithOwner, "/")
+	if !ok {
+		return Readme{}, fmt.Errorf("invalid repository name %q", nameWithOwner)
+	}
+
+	select {
+	case <-ctx.Done():
+		return Readme{}, ctx.Err()
+	default:
+	}
+
+	var query struct {
+		Repository *struct {
+			ReadmeMD    *readmeObject `graphql:"readmeMD: object(expression: \"HEAD:README.md\")"`
+			ReadmeLower *readmeObject `graphql:"readmeLower: object(expression: \"HEAD:readme.md\")"`
+			ReadmeTitle *readmeObject `graphql:"readmeTitle: object(expression: \"HEAD:Readme.md\")"`
+			ReadmePlain *readmeObject `graphql:"readmePlain: object(expression: \"HEAD:README\")"`
+			ReadmeRst   *readmeObject `graphql:"readmeRst: object(expression: \"HEAD:README.rst\")"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]any{
+		"owner": graphql.String(owner),
+		"name":  graphql.String(name),
+	}
+
+	if err := client.QueryWithContext(ctx, "RepositoryReadme", &query, variables); err != nil {
+		return Readme{}, err
+	}
+	if query.Repository == nil {
+		return Readme{}, fmt.Errorf("repository %s not found", nameWithOwner)
+	}
+
+	readme := Readme{Repo: nameWithOwner, FetchedAt: time.Now().UTC()}
+	candidates := []*readmeObject{
+		query.Repository.ReadmeMD,
+		query.Repository.ReadmeLower,
+		query.Repository.ReadmeTitle,
+		query.Repository.ReadmePlain,
+		query.Repository.ReadmeRst,
+	}
+	for _, candidate := range candidates {
+		if candidate == nil || candidate.Blob.IsBinary {
+			continue
+		}
+		readme.Text = candidate.Blob.Text
+		break
+	}
+
+	return readme, nil
+}
+
+func ReadmeDir(cachePath string) string {
+	if cachePath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(cachePath), "readme")
+}
+
+func LoadReadme(dir, nameWithOwner string) (Readme, error) {
+	if dir == "" {
+		return Readme{}, nil
+	}
+
+	content, err := os.ReadFile(repoFilePath(dir, nameWithOwner))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Readme{}, nil
+		}
+		return Readme{}, err
+	}
+
+	var readme Readme
+	if err := json.Unmarshal(content, &readme); err != nil {
+		return Readme{}, err
+	}
+	return readme, nil
+}
+
+func SaveReadme(dir string, readme Readme) error {
+	if dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(readme)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(repoFilePath(dir, readme.Repo), content, 0o644)
+}
+
+func ReadmeIsStale(readme Readme, ttl time.Duration) bool {
+	if readme.FetchedAt.IsZero() {
+		return true
+	}
+	return ttl > 0 && time.Since(readme.FetchedAt) >= ttl
+}
+
+// repoFilePath names the per-repo file of the README and details caches.
+func repoFilePath(dir, nameWithOwner string) string {
+	return filepath.Join(dir, strings.ReplaceAll(nameWithOwner, "/", "__")+".json")
+}
@@ -0,0 +1,144 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// MaxRetries is how many times a failed request is retried before the error
+// is reported.
+const MaxRetries = 5
+
+const (
+	retryBase          = 2 * time.Second
+	retryMax           = 2 * time.Minute
+	secondaryRateLimit = time.Minute
+)
+
+// RateLimit is the GraphQL budget reported alongside a query.
+type RateLimit struct {
+	Remaining int
+	ResetAt   time.Time
+	Cost      int
+}
+
+// Wait is how long to hold off before the next query of the same cost, which
+// is zero unless the budget is used up.
+func (r RateLimit) Wait(now time.Time) time.Duration {
+	if r.ResetAt.IsZero() || r.Remaining >= max(r.Cost, 1) {
+		return 0
+	}
+	return max(0, r.ResetAt.Sub(now))
+}
+
+// RetryDelay reports whether a request that failed with err on the given
+// attempt (starting at 1) is worth retrying, and after how long. Rate limits
+// wait for their reset; server errors and network failures back off
+// exponentially with jitter.
+func RetryDelay(err error, attempt int, now time.Time) (time.Duration, bool) {
+	if err == nil || attempt > MaxRetries || errors.Is(err, context.Canceled) {
+		return 0, false
+	}
+
+	var httpErr *gh.HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case isRateLimited(httpErr):
+			return rateLimitDelay(httpErr.Headers, attempt, now), true
+		case httpErr.StatusCode >= 500:
+			return backoff(attempt), true
+		}
+		return 0, false
+	}
+
+	var gqlErr *gh.GraphQLError
+	if errors.As(err, &gqlErr) {
+		for _, item := range gqlErr.Errors {
+			if item.Type == "RATE_LIMITED" {
+				return max(secondaryRateLimit, backoff(attempt)), true
+			}
+		}
+		return 0, false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return backoff(attempt), true
+	}
+	return 0, false
+}
+
+func isRateLimited(err *gh.HTTPError) bool {
+	if err.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return err.StatusCode == http.StatusForbidden &&
+		(err.Headers.Get("X-RateLimit-Remaining") == "0" || strings.Contains(strings.ToLower(err.Message), "rate limit"))
+}
+
+// rateLimitDelay follows Retry-After, then the primary limit's reset time,
+// and otherwise waits out a secondary limit.
+func rateLimitDelay(headers http.Header, attempt int, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(headers.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if headers.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(headers.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(0, time.Unix(reset, 0).Sub(now))
+		}
+	}
+	return max(secondaryRateLimit, backoff(attempt))
+}
+
+func backoff(attempt int) time.Duration {
+	d := retryBase << (attempt - 1)
+	if d <= 0 || d > retryMax {
+		d = retryMax
+	}
+	// Half fixed, half random, so concurrent clients spread out.
+	return d/2 + rand.N(d/2+1)
+}
+
+// fetchPage is FetchStarsPage for callers that can block: it retries
+// transient failures and, when the previous page used up the rate limit,
+// waits for the reset first.
+func fetchPage(ctx context.Context, source StarSource, pageSize int, after *string, limit RateLimit) (StarsPage, error) {
+	if err := sleep(ctx, limit.Wait(time.Now())); err != nil {
+		return StarsPage{}, err
+	}
+	for attempt := 1; ; attempt++ {
+		page, err := source.FetchStarsPage(ctx, pageSize, after)
+		if err == nil {
+			return page, nil
+		}
+		delay, retry := RetryDelay(err, attempt, time.Now())
+		if !retry {
+			return StarsPage{}, err
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return StarsPage{}, err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
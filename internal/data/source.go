@@ -0,0 +1,127 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	gh "github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// DefaultHost is where stars come from unless gh is configured otherwise.
+const DefaultHost = "github.com"
+
+// StarSource lists the starred repositories of one account on one GitHub
+// host, newest star first.
+type StarSource interface {
+	FetchStarsPage(ctx context.Context, pageSize int, after *string) (StarsPage, error)
+	// Client talks to the source's host. It is also used for everything that
+	// is not a listing of stars: READMEs, metadata and mutations.
+	Client() *gh.GraphQLClient
+	Host() string
+	// Login is the account whose stars are listed, or "" for the
+	// authenticated user.
+	Login() string
+}
+
+// ViewerSource lists the authenticated user's stars on github.com or a GitHub
+// Enterprise Server host.
+type ViewerSource struct {
+	client *gh.GraphQLClient
+	host   string
+}
+
+func NewViewerSource(client *gh.GraphQLClient, host string) *ViewerSource {
+	return &ViewerSource{client: client, host: host}
+}
+
+func (s *ViewerSource) FetchStarsPage(ctx context.Context, pageSize int, after *string) (StarsPage, error) {
+	return fetchViewerStars(ctx, s.client, pageSize, after)
+}
+
+func (s *ViewerSource) Client() *gh.GraphQLClient { return s.client }
+func (s *ViewerSource) Host() string              { return s.host }
+func (s *ViewerSource) Login() string             { return "" }
+
+// UserSource lists the public stars of another user.
+type UserSource struct {
+	client *gh.GraphQLClient
+	host   string
+	login  string
+}
+
+func NewUserSource(client *gh.GraphQLClient, host, login string) *UserSource {
+	return &UserSource{client: client, host: host, login: login}
+}
+
+func (s *UserSource) FetchStarsPage(ctx context.Context, pageSize int, after *string) (StarsPage, error) {
+	return fetchUserStars(ctx, s.client, s.login, pageSize, after)
+}
+
+func (s *UserSource) Client() *gh.GraphQLClient { return s.client }
+func (s *UserSource) Host() string              { return s.host }
+func (s *UserSource) Login() string             { return s.login }
+
+// ResolveHost normalizes host, falling back to gh's default host.
+func ResolveHost(host string) string {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		host, _ = auth.DefaultHost()
+	}
+	return auth.NormalizeHostname(host)
+}
+
+// NewStarSource lists the stars of login on host, or of the authenticated
+// user when login is empty. client may be nil when only the cache is read.
+func NewStarSource(client *gh.GraphQLClient, host, login string) StarSource {
+	login = strings.TrimPrefix(strings.TrimSpace(login), "@")
+	if login == "" {
+		return NewViewerSource(client, host)
+	}
+	return NewUserSource(client, host, login)
+}
+
+// IsReadOnly reports whether source lists someone else's stars, which the
+// viewer cannot unstar or sort into lists.
+func IsReadOnly(source StarSource) bool {
+	return source != nil && source.Login() != ""
+}
+
+// SourceName describes source for the status bar, e.g. "octocat@github.com".
+// The authenticated user on the default host has no name.
+func SourceName(source StarSource) string {
+	if source == nil {
+		return ""
+	}
+	name := source.Login()
+	if source.Host() != DefaultHost {
+		if name == "" {
+			return source.Host()
+		}
+		name += "@" + source.Host()
+	}
+	return name
+}
+
+// SourceCachePath gives every source its own cache file next to path. The
+// authenticated user on the default host keeps path itself, so existing
+// caches stay in use.
+func SourceCachePath(path string, source StarSource) string {
+	name := SourceName(source)
+	if path == "" || name == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + sanitizeFileName(name) + ext
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		}
+		return '_'
+	}, name)
+}
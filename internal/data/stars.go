@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 )
 
 type Repo struct {
+	ID              string
 	Name            string
 	NameWithOwner   string
 	Description     string
@@ -18,8 +20,12 @@ type Repo struct {
 	Stars           int
 	PrimaryLanguage string
 	UpdatedAt       time.Time
+	PushedAt        time.Time
 	StarredAt       time.Time
 	IsFork          bool
+	IsArchived      bool
+	IsDisabled      bool
+	IsMirror        bool
 	Topics          []string
 }
 
@@ -28,6 +34,7 @@ type StarsPage struct {
 	TotalCount int
 	EndCursor  string
 	HasNext    bool
+	RateLimit  RateLimit
 }
 
 type topicNode struct {
@@ -36,97 +43,156 @@ type topicNode struct {
 	}
 }
 
-func FetchStarsPage(ctx context.Context, client *gh.GraphQLClient, pageSize int, after *string) (StarsPage, error) {
-	if client == nil {
-		return StarsPage{}, errors.New("nil GraphQL client")
+type repoNode struct {
+	ID              string `graphql:"id"`
+	Name            string
+	NameWithOwner   string `graphql:"nameWithOwner"`
+	Description     string
+	URL             string
+	Stars           int `graphql:"stargazerCount"`
+	UpdatedAt       time.Time
+	PushedAt        time.Time
+	IsFork          bool `graphql:"isFork"`
+	IsArchived      bool `graphql:"isArchived"`
+	IsDisabled      bool `graphql:"isDisabled"`
+	IsMirror        bool `graphql:"isMirror"`
+	PrimaryLanguage *struct {
+		Name string
 	}
+	RepositoryTopics struct {
+		Nodes []topicNode
+	} `graphql:"repositoryTopics(first: 5)"`
+}
 
-	select {
-	case <-ctx.Done():
-		return StarsPage{}, ctx.Err()
-	default:
+func (node repoNode) toRepo(starredAt time.Time) Repo {
+	primaryLanguage := ""
+	if node.PrimaryLanguage != nil {
+		primaryLanguage = node.PrimaryLanguage.Name
 	}
 
-	var query struct {
-		Viewer struct {
-			StarredRepositories struct {
-				Edges []struct {
-					StarredAt time.Time `graphql:"starredAt"`
-					Node      struct {
-						Name            string
-						NameWithOwner   string `graphql:"nameWithOwner"`
-						Description     string
-						URL             string
-						Stars           int `graphql:"stargazerCount"`
-						UpdatedAt       time.Time
-						IsFork          bool `graphql:"isFork"`
-						PrimaryLanguage *struct {
-							Name string
-						}
-						RepositoryTopics struct {
-							Nodes []topicNode
-						} `graphql:"repositoryTopics(first: 5)"`
-					}
-				}
-				TotalCount int
-				PageInfo   struct {
-					HasNextPage bool
-					EndCursor   graphql.String
-				}
-			} `graphql:"starredRepositories(first: $first, after: $after, orderBy: {field: STARRED_AT, direction: DESC})"`
+	topics := make([]string, 0, len(node.RepositoryTopics.Nodes))
+	for _, topic := range node.RepositoryTopics.Nodes {
+		name := strings.TrimSpace(topic.Topic.Name)
+		if name == "" {
+			continue
 		}
+		topics = append(topics, name)
+	}
+
+	return Repo{
+		ID:              node.ID,
+		Name:            node.Name,
+		NameWithOwner:   node.NameWithOwner,
+		Description:     strings.TrimSpace(node.Description),
+		URL:             node.URL,
+		Stars:           node.Stars,
+		PrimaryLanguage: primaryLanguage,
+		UpdatedAt:       node.UpdatedAt,
+		PushedAt:        node.PushedAt,
+		StarredAt:       starredAt,
+		IsFork:          node.IsFork,
+		IsArchived:      node.IsArchived,
+		IsDisabled:      node.IsDisabled,
+		IsMirror:        node.IsMirror,
+		Topics:          topics,
+	}
+}
+
+// starredConnection is the shape of starredRepositories on both the viewer
+// and other users.
+type starredConnection struct {
+	Edges []struct {
+		StarredAt time.Time `graphql:"starredAt"`
+		Node      repoNode
+	}
+	TotalCount int
+	PageInfo   struct {
+		HasNextPage bool
+		EndCursor   graphql.String
+	}
+}
+
+func (c starredConnection) toPage() StarsPage {
+	repos := make([]Repo, 0, len(c.Edges))
+	for _, edge := range c.Edges {
+		repos = append(repos, edge.Node.toRepo(edge.StarredAt))
 	}
 
+	return StarsPage{
+		Repos:      repos,
+		TotalCount: c.TotalCount,
+		EndCursor:  string(c.PageInfo.EndCursor),
+		HasNext:    c.PageInfo.HasNextPage,
+	}
+}
+
+func pageVariables(pageSize int, after *string) map[string]any {
 	var afterVar *graphql.String
 	if after != nil {
 		v := graphql.String(*after)
 		afterVar = &v
 	}
 
-	variables := map[string]any{
+	return map[string]any{
 		"first": graphql.Int(pageSize),
 		"after": afterVar,
 	}
+}
 
-	if err := client.Query("ViewerStars", &query, variables); err != nil {
-		return StarsPage{}, err
+func fetchViewerStars(ctx context.Context, client *gh.GraphQLClient, pageSize int, after *string) (StarsPage, error) {
+	if client == nil {
+		return StarsPage{}, errors.New("nil GraphQL client")
 	}
 
-	repos := make([]Repo, 0, len(query.Viewer.StarredRepositories.Edges))
-	for _, edge := range query.Viewer.StarredRepositories.Edges {
-		node := edge.Node
-		primaryLanguage := ""
-		if node.PrimaryLanguage != nil {
-			primaryLanguage = node.PrimaryLanguage.Name
-		}
+	select {
+	case <-ctx.Done():
+		return StarsPage{}, ctx.Err()
+	default:
+	}
 
-		topics := make([]string, 0, len(node.RepositoryTopics.Nodes))
-		for _, topic := range node.RepositoryTopics.Nodes {
-			name := strings.TrimSpace(topic.Topic.Name)
-			if name == "" {
-				continue
-			}
-			topics = append(topics, name)
+	var query struct {
+		Viewer struct {
+			StarredRepositories starredConnection `graphql:"starredRepositories(first: $first, after: $after, orderBy: {field: STARRED_AT, direction: DESC})"`
 		}
+		RateLimit RateLimit
+	}
 
-		repos = append(repos, Repo{
-			Name:            node.Name,
-			NameWithOwner:   node.NameWithOwner,
-			Description:     strings.TrimSpace(node.Description),
-			URL:             node.URL,
-			Stars:           node.Stars,
-			PrimaryLanguage: primaryLanguage,
-			UpdatedAt:       node.UpdatedAt,
-			StarredAt:       edge.StarredAt,
-			IsFork:          node.IsFork,
-			Topics:          topics,
-		})
+	if err := client.QueryWithContext(ctx, "ViewerStars", &query, pageVariables(pageSize, after)); err != nil {
+		return StarsPage{}, err
 	}
+	page := query.Viewer.StarredRepositories.toPage()
+	page.RateLimit = query.RateLimit
+	return page, nil
+}
 
-	return StarsPage{
-		Repos:      repos,
-		TotalCount: query.Viewer.StarredRepositories.TotalCount,
-		EndCursor:  string(query.Viewer.StarredRepositories.PageInfo.EndCursor),
-		HasNext:    query.Viewer.StarredRepositories.PageInfo.HasNextPage,
-	}, nil
+func fetchUserStars(ctx context.Context, client *gh.GraphQLClient, login string, pageSize int, after *string) (StarsPage, error) {
+	if client == nil {
+		return StarsPage{}, errors.New("nil GraphQL client")
+	}
+
+	select {
+	case <-ctx.Done():
+		return StarsPage{}, ctx.Err()
+	default:
+	}
+
+	var query struct {
+		User *struct {
+			StarredRepositories starredConnection `graphql:"starredRepositories(first: $first, after: $after, orderBy: {field: STARRED_AT, direction: DESC})"`
+		} `graphql:"user(login: $login)"`
+		RateLimit RateLimit
+	}
+
+	variables := pageVariables(pageSize, after)
+	variables["login"] = graphql.String(login)
+
+	if err := client.QueryWithContext(ctx, "UserStars", &query, variables); err != nil {
+		return StarsPage{}, err
+	}
+	if query.User == nil {
+		return StarsPage{}, fmt.Errorf("user %s not found", login)
+	}
+	page := query.User.StarredRepositories.toPage()
+	page.RateLimit = query.RateLimit
+	return page, nil
 }
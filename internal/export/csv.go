@@ -0,0 +1,40 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+func writeCSV(w io.Writer, repos []data.Repo, opts Options) error {
+	out := csv.NewWriter(w)
+	header := []string{"name", "url", "description", "language", "stars", "topics", "fork", "starred_at", "updated_at", "tags", "note"}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		annotation := opts.Annotations[repo.NameWithOwner]
+		row := []string{
+			repo.NameWithOwner,
+			repo.URL,
+			repo.Description,
+			repo.PrimaryLanguage,
+			fmt.Sprintf("%d", repo.Stars),
+			strings.Join(repo.Topics, ";"),
+			fmt.Sprintf("%t", repo.IsFork),
+			formatDate(repo.StarredAt),
+			formatDate(repo.UpdatedAt),
+			strings.Join(annotation.Tags, ";"),
+			annotation.Note,
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
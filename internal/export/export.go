@@ -0,0 +1,177 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+var Formats = []string{"markdown", "csv", "html", "opml"}
+
+var GroupModes = []string{"language", "topic", "none"}
+
+type Options struct {
+	Format      string
+	GroupBy     string
+	Title       string
+	Annotations data.Annotations
+	Now         time.Time
+}
+
+// Write renders repos in the given order. Grouped formats keep that order
+// within each group.
+func Write(w io.Writer, repos []data.Repo, opts Options) error {
+	if opts.Title == "" {
+		opts.Title = "GitHub Stars"
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.GroupBy == "" {
+		opts.GroupBy = "language"
+	}
+	if err := ValidateGroupBy(opts.GroupBy); err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case "markdown":
+		return writeMarkdown(w, repos, opts)
+	case "csv":
+		return writeCSV(w, repos, opts)
+	case "html":
+		return writeHTML(w, repos, opts)
+	case "opml":
+		return writeOPML(w, repos, opts)
+	}
+	return ValidateFormat(opts.Format)
+}
+
+func ValidateFormat(format string) error {
+	for _, candidate := range Formats {
+		if candidate == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown export format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+func ValidateGroupBy(mode string) error {
+	for _, candidate := range GroupModes {
+		if candidate == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown group %q (want %s)", mode, strings.Join(GroupModes, ", "))
+}
+
+// FormatFromPath guesses the format from a file extension.
+func FormatFromPath(path string) (string, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return "markdown", true
+	case ".csv":
+		return "csv", true
+	case ".html", ".htm":
+		return "html", true
+	case ".opml", ".xml":
+		return "opml", true
+	}
+	return "", false
+}
+
+func Extension(format string) string {
+	switch format {
+	case "markdown":
+		return ".md"
+	case "csv":
+		return ".csv"
+	case "html":
+		return ".html"
+	case "opml":
+		return ".opml"
+	}
+	return ".txt"
+}
+
+type group struct {
+	name  string
+	repos []data.Repo
+}
+
+// groupRepos buckets repos by language or topic. With topics a repo is listed
+// under each of its topics. Groups are ordered by size, then name, with the
+// catch-all group last.
+func groupRepos(repos []data.Repo, mode string) []group {
+	if mode == "none" {
+		return []group{{repos: repos}}
+	}
+
+	const other = "Other"
+	index := map[string]int{}
+	groups := []group{}
+	add := func(name string, repo data.Repo) {
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, group{name: name})
+		}
+		groups[i].repos = append(groups[i].repos, repo)
+	}
+
+	for _, repo := range repos {
+		switch mode {
+		case "language":
+			name := repo.PrimaryLanguage
+			if name == "" {
+				name = other
+			}
+			add(name, repo)
+		case "topic":
+			if len(repo.Topics) == 0 {
+				add(other, repo)
+			}
+			for _, topic := range repo.Topics {
+				add(topic, repo)
+			}
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].name == other) != (groups[j].name == other) {
+			return groups[j].name == other
+		}
+		if len(groups[i].repos) != len(groups[j].repos) {
+			return len(groups[i].repos) > len(groups[j].repos)
+		}
+		return strings.ToLower(groups[i].name) < strings.ToLower(groups[j].name)
+	})
+	return groups
+}
+
+func compactCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return trimZero(fmt.Sprintf("%.1f", float64(n)/1_000_000)) + "m"
+	case n >= 1_000:
+		return trimZero(fmt.Sprintf("%.1f", float64(n)/1_000)) + "k"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func trimZero(s string) string {
+	return strings.TrimSuffix(s, ".0")
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+var testRepos = []data.Repo{
+	{NameWithOwner: "charmbracelet/bubbletea", URL: "https://github.com/charmbracelet/bubbletea", Description: "A TUI *framework*", PrimaryLanguage: "Go", Stars: 28_400, Topics: []string{"tui", "cli"}},
+	{NameWithOwner: "junegunn/fzf", URL: "https://github.com/junegunn/fzf", Description: "Fuzzy finder", PrimaryLanguage: "Go", Stars: 1_200_000, Topics: []string{"cli"}},
+	{NameWithOwner: "a/dotfiles", URL: "https://github.com/a/dotfiles", Description: "My <dotfiles>", Stars: 3},
+}
+
+var testAnnotations = data.Annotations{
+	"junegunn/fzf": {Tags: []string{"daily"}, Note: "pairs with ripgrep"},
+}
+
+func writeTest(t *testing.T, format, group string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	err := Write(&buf, testRepos, Options{
+		Format:      format,
+		GroupBy:     group,
+		Annotations: testAnnotations,
+		Now:         time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Write(%s, %s): %v", format, group, err)
+	}
+	return buf.String()
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format, group string
+		want          []string
+	}{
+		{"markdown", "language", []string{
+			"# GitHub Stars\n\n3 repositories · exported 2024-06-15\n",
+			"## Contents\n\n- [Go](#go) (2)\n- [Other](#other) (1)\n",
+			"## Go\n\n- [charmbracelet/bubbletea](https://github.com/charmbracelet/bubbletea) - A TUI \\*framework\\* ★ 28.4k\n",
+			"- [junegunn/fzf](https://github.com/junegunn/fzf) - Fuzzy finder ★ 1.2m `#daily`\n",
+			"## Other\n\n- [a/dotfiles](https://github.com/a/dotfiles) - My \\<dotfiles\\> ★ 3\n",
+		}},
+		{"markdown", "topic", []string{
+			"- [cli](#cli) (2)\n- [tui](#tui) (1)\n- [Other](#other) (1)\n",
+		}},
+		{"html", "language", []string{
+			`<a href="#go">Go (2)</a>`,
+			`<h2 id="other">Other</h2>`,
+			`<a href="https://github.com/junegunn/fzf">junegunn/fzf</a> <span class="meta">★ 1.2m · Go</span>`,
+			`My &lt;dotfiles&gt;`,
+			`<span class="tag">#daily</span>`,
+			`<p class="note">pairs with ripgrep</p>`,
+		}},
+		{"html", "none", []string{
+			`<p class="summary">3 repositories · exported 2024-06-15</p>`,
+		}},
+		{"opml", "none", []string{
+			`<outline text="junegunn/fzf" title="junegunn/fzf" type="rss" xmlUrl="https://github.com/junegunn/fzf/releases.atom" htmlUrl="https://github.com/junegunn/fzf"></outline>`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.group, func(t *testing.T) {
+			got := writeTest(t, tt.format, tt.group)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output does not contain %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteUngroupedHasNoContents(t *testing.T) {
+	for _, format := range []string{"markdown", "html"} {
+		got := writeTest(t, format, "none")
+		if strings.Contains(got, "Contents") || strings.Contains(got, "<nav>") {
+			t.Errorf("%s without groups has a table of contents:\n%s", format, got)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(writeTest(t, "csv", "language"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"name", "url", "description", "language", "stars", "topics", "fork", "starred_at", "updated_at", "tags", "note"},
+		{"charmbracelet/bubbletea", "https://github.com/charmbracelet/bubbletea", "A TUI *framework*", "Go", "28400", "tui;cli", "false", "", "", "", ""},
+		{"junegunn/fzf", "https://github.com/junegunn/fzf", "Fuzzy finder", "Go", "1200000", "cli", "false", "", "", "daily", "pairs with ripgrep"},
+		{"a/dotfiles", "https://github.com/a/dotfiles", "My <dotfiles>", "", "3", "", "false", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestWriteOPML(t *testing.T) {
+	var doc opmlDocument
+	if err := xml.Unmarshal([]byte(writeTest(t, "opml", "language")), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "GitHub Stars" || doc.Created != "Sat, 15 Jun 2024 00:00:00 +0000" {
+		t.Errorf("head = %q, %q", doc.Title, doc.Created)
+	}
+	groups := []string{}
+	for _, outline := range doc.Body {
+		groups = append(groups, outline.Text)
+	}
+	if want := []string{"Go", "Other"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v", groups, want)
+	}
+	if got := doc.Body[1].Children[0].XMLURL; got != "https://github.com/a/dotfiles/releases.atom" {
+		t.Errorf("feed = %q", got)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	tests := []struct {
+		format, group string
+		want          string
+	}{
+		{"pdf", "", `unknown export format "pdf" (want markdown, csv, html, opml)`},
+		{"", "", `unknown export format "" (want markdown, csv, html, opml)`},
+		{"csv", "owner", `unknown group "owner" (want language, topic, none)`},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		err := Write(&buf, testRepos, Options{Format: tt.format, GroupBy: tt.group})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Write(%q, %q) error = %v, want %q", tt.format, tt.group, err, tt.want)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Write(%q, %q) wrote %q before failing", tt.format, tt.group, buf.String())
+		}
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"stars.md", "markdown", true},
+		{"stars.MARKDOWN", "markdown", true},
+		{"out/stars.csv", "csv", true},
+		{"stars.htm", "html", true},
+		{"feeds.xml", "opml", true},
+		{"stars.json", "", false},
+		{"stars", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := FormatFromPath(tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FormatFromPath(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+		if ok && ValidateFormat(got) != nil {
+			t.Errorf("FormatFromPath(%q) = %q, which ValidateFormat rejects", tt.path, got)
+		}
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	a := newAnchors()
+	got := []string{a.next("C++"), a.next("Jupyter Notebook"), a.next("c"), a.next("C#"), a.next("Vim script")}
+	want := []string{"c", "jupyter-notebook", "c-1", "c-2", "vim-script"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("anchors = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type htmlRepo struct {
+	Name        string
+	URL         string
+	Description string
+	Language    string
+	Stars       string
+	Topics      []string
+	Tags        []string
+	Note        string
+}
+
+type htmlGroup struct {
+	Name   string
+	Anchor string
+	Repos  []htmlRepo
+}
+
+type htmlPage struct {
+	Title    string
+	Count    int
+	Exported string
+	Groups   []htmlGroup
+}
+
+func writeHTML(w io.Writer, repos []data.Repo, opts Options) error {
+	page := htmlPage{
+		Title:    opts.Title,
+		Count:    len(repos),
+		Exported: opts.Now.Format("2006-01-02"),
+	}
+
+	anchors := newAnchors()
+	for _, g := range groupRepos(repos, opts.GroupBy) {
+		hg := htmlGroup{Name: g.name}
+		if g.name != "" {
+			hg.Anchor = anchors.next(g.name)
+		}
+		for _, repo := range g.repos {
+			annotation := opts.Annotations[repo.NameWithOwner]
+			hg.Repos = append(hg.Repos, htmlRepo{
+				Name:        repo.NameWithOwner,
+				URL:         repo.URL,
+				Description: repo.Description,
+				Language:    repo.PrimaryLanguage,
+				Stars:       compactCount(repo.Stars),
+				Topics:      repo.Topics,
+				Tags:        annotation.Tags,
+				Note:        annotation.Note,
+			})
+		}
+		page.Groups = append(page.Groups, hg)
+	}
+
+	return htmlTemplate.Execute(w, page)
+}
+
+var htmlTemplate = template.Must(template.New("stars").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+  h1 { margin-bottom: 0; }
+  .summary, .meta { color: #656d76; }
+  nav a { margin-right: .75rem; white-space: nowrap; }
+  h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; margin-top: 2rem; }
+  ul { list-style: none; padding: 0; }
+  li { margin: 0 0 1rem; }
+  li > a { font-weight: 600; color: #0969da; text-decoration: none; }
+  .topic, .tag { display: inline-block; font-size: 12px; border-radius: 1em; padding: 0 .6em; margin: .2rem .2rem 0 0; }
+  .topic { background: #ddf4ff; color: #0969da; }
+  .tag { background: #fff8c5; color: #7d4e00; }
+  .note { font-style: italic; color: #656d76; margin: .2rem 0 0; }
+  @media (prefers-color-scheme: dark) {
+    body { background: #0d1117; color: #e6edf3; }
+    h2 { border-color: #30363d; }
+    li > a, nav a { color: #4493f8; }
+    .topic { background: #121d2f; color: #4493f8; }
+    .tag { background: #272115; color: #d29922; }
+  }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="summary">{{.Count}} repositories · exported {{.Exported}}</p>
+{{- if gt (len .Groups) 1}}
+<nav>{{range .Groups}}<a href="#{{.Anchor}}">{{.Name}} ({{len .Repos}})</a> {{end}}</nav>
+{{- end}}
+{{range .Groups}}
+{{- if .Name}}<h2 id="{{.Anchor}}">{{.Name}}</h2>{{end}}
+<ul>
+{{- range .Repos}}
+  <li>
+    <a href="{{.URL}}">{{.Name}}</a> <span class="meta">★ {{.Stars}}{{if .Language}} · {{.Language}}{{end}}</span>
+    {{- if .Description}}<br>{{.Description}}{{end}}
+    {{- if or .Topics .Tags}}<br>{{range .Topics}}<span class="topic">{{.}}</span>{{end}}{{range .Tags}}<span class="tag">#{{.}}</span>{{end}}{{end}}
+    {{- if .Note}}<p class="note">{{.Note}}</p>{{end}}
+  </li>
+{{- end}}
+</ul>
+{{end -}}
+</body>
+</html>
+`))
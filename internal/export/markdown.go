@@ -0,0 +1,88 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+func writeMarkdown(w io.Writer, repos []data.Repo, opts Options) error {
+	out := bufio.NewWriter(w)
+	groups := groupRepos(repos, opts.GroupBy)
+
+	fmt.Fprintf(out, "# %s\n\n", opts.Title)
+	fmt.Fprintf(out, "%d repositories · exported %s\n", len(repos), opts.Now.Format("2006-01-02"))
+
+	if len(groups) > 1 {
+		anchors := newAnchors()
+		out.WriteString("\n## Contents\n\n")
+		for _, g := range groups {
+			fmt.Fprintf(out, "- [%s](#%s) (%d)\n", escapeMarkdown(g.name), anchors.next(g.name), len(g.repos))
+		}
+	}
+
+	for _, g := range groups {
+		out.WriteString("\n")
+		if g.name != "" {
+			fmt.Fprintf(out, "## %s\n\n", escapeMarkdown(g.name))
+		}
+		for _, repo := range g.repos {
+			out.WriteString(markdownItem(repo, opts.Annotations[repo.NameWithOwner]))
+			out.WriteString("\n")
+		}
+	}
+	return out.Flush()
+}
+
+func markdownItem(repo data.Repo, annotation data.Annotation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "- [%s](%s)", escapeMarkdown(repo.NameWithOwner), repo.URL)
+	if desc := strings.Join(strings.Fields(repo.Description), " "); desc != "" {
+		b.WriteString(" - ")
+		b.WriteString(escapeMarkdown(desc))
+	}
+	fmt.Fprintf(&b, " ★ %s", compactCount(repo.Stars))
+	for _, tag := range annotation.Tags {
+		fmt.Fprintf(&b, " `#%s`", tag)
+	}
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// anchors reproduces GitHub's heading slugs, including the numeric suffix
+// added to repeated headings.
+type anchors map[string]int
+
+func newAnchors() anchors {
+	return anchors{}
+}
+
+func (a anchors) next(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	slug := b.String()
+	n := a[slug]
+	a[slug] = n + 1
+	if n > 0 {
+		return fmt.Sprintf("%s-%d", slug, n)
+	}
+	return slug
+}
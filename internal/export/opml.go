@@ -0,0 +1,66 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type opmlDocument struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Title   string        `xml:"head>title"`
+	Created string        `xml:"head>dateCreated"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Children []opmlOutline `xml:"outline"`
+}
+
+// writeOPML emits one feed per repo pointing at its releases, so the export
+// can be imported into a feed reader to follow new versions.
+func writeOPML(w io.Writer, repos []data.Repo, opts Options) error {
+	doc := opmlDocument{
+		Version: "2.0",
+		Title:   opts.Title,
+		Created: opts.Now.UTC().Format(time.RFC1123Z),
+	}
+
+	for _, g := range groupRepos(repos, opts.GroupBy) {
+		outlines := make([]opmlOutline, 0, len(g.repos))
+		for _, repo := range g.repos {
+			outlines = append(outlines, opmlOutline{
+				Text:    repo.NameWithOwner,
+				Title:   repo.NameWithOwner,
+				Type:    "rss",
+				XMLURL:  strings.TrimSuffix(repo.URL, "/") + "/releases.atom",
+				HTMLURL: repo.URL,
+			})
+		}
+		if g.name == "" {
+			doc.Body = append(doc.Body, outlines...)
+			continue
+		}
+		doc.Body = append(doc.Body, opmlOutline{Text: g.name, Title: g.name, Children: outlines})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
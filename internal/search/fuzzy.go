@@ -0,0 +1,151 @@
+package search
+
+import "unicode"
+
+const (
+	scoreMatch       = 16
+	scoreConsecutive = 12
+	scoreBoundary    = 10
+	scoreFirstChar   = 8
+	penaltyGapStart  = 3
+	penaltyGapExtend = 1
+)
+
+// fuzzyMatch reports whether every rune of pattern appears in text in order.
+// The returned positions are rune offsets into text. pattern must already be
+// lower case.
+func fuzzyMatch(pattern, text string) (int, []int, bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	needle := []rune(pattern)
+	orig, hay := lowerRunes(text)
+	if len(needle) > len(hay) {
+		return 0, nil, false
+	}
+
+	best := -1
+	var bestPos []int
+
+	if idx := indexRunes(hay, needle); idx >= 0 {
+		pos := make([]int, len(needle))
+		for i := range pos {
+			pos[i] = idx + i
+		}
+		best = scorePositions(orig, pos)
+		bestPos = pos
+	}
+
+	// Greedy forward scan to find the earliest end, then scan backwards from
+	// that end to tighten the window, as in fzf's v1 algorithm.
+	end := -1
+	for i, j := 0, 0; i < len(hay); i++ {
+		if hay[i] == needle[j] {
+			j++
+			if j == len(needle) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		if best < 0 {
+			return 0, nil, false
+		}
+		return best, bestPos, true
+	}
+
+	pos := make([]int, len(needle))
+	for i, j := end, len(needle)-1; i >= 0 && j >= 0; i-- {
+		if hay[i] == needle[j] {
+			pos[j] = i
+			j--
+		}
+	}
+	if score := scorePositions(orig, pos); score > best {
+		best = score
+		bestPos = pos
+	}
+
+	return best, bestPos, true
+}
+
+// substringMatch is the exact counterpart of fuzzyMatch used for phrases.
+func substringMatch(pattern, text string) (int, []int, bool) {
+	orig, hay := lowerRunes(text)
+	needle := []rune(pattern)
+	idx := indexRunes(hay, needle)
+	if idx < 0 {
+		return 0, nil, false
+	}
+	pos := make([]int, len(needle))
+	for i := range pos {
+		pos[i] = idx + i
+	}
+	return scorePositions(orig, pos), pos, true
+}
+
+// lowerRunes lowers rune by rune so offsets into the result stay valid for
+// the original text.
+func lowerRunes(text string) ([]rune, []rune) {
+	orig := []rune(text)
+	lower := make([]rune, len(orig))
+	for i, r := range orig {
+		lower[i] = unicode.ToLower(r)
+	}
+	return orig, lower
+}
+
+func scorePositions(text []rune, pos []int) int {
+	score := 0
+	for i, p := range pos {
+		score += scoreMatch
+		if p == 0 {
+			score += scoreFirstChar
+		}
+		if isBoundary(text, p) {
+			score += scoreBoundary
+		}
+		if i > 0 {
+			gap := p - pos[i-1] - 1
+			if gap == 0 {
+				score += scoreConsecutive
+			} else {
+				score -= penaltyGapStart + (gap-1)*penaltyGapExtend
+			}
+		}
+	}
+	return score
+}
+
+func isBoundary(text []rune, p int) bool {
+	if p == 0 {
+		return true
+	}
+	prev, cur := text[p-1], text[p]
+	switch prev {
+	case '/', '-', '_', '.', ' ', ',', ':':
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+func indexRunes(hay, needle []rune) int {
+	if len(needle) == 0 {
+		return 0
+	}
+	for i := 0; i+len(needle) <= len(hay); i++ {
+		match := true
+		for j := range needle {
+			if hay[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
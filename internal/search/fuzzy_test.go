@@ -0,0 +1,152 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    []int
+		ok      bool
+	}{
+		{"", "anything", nil, true},
+		{"tea", "charmbracelet/bubbletea", []int{20, 21, 22}, true},
+		{"bt", "charmbracelet/bubbletea", []int{5, 12}, true},
+		{"cbt", "charmbracelet/bubbletea", []int{0, 5, 12}, true},
+		{"gh", "cli/gh-stars", []int{4, 5}, true},
+		{"ghs", "cli/gh-stars", []int{4, 5, 7}, true},
+		{"ls", "CamelCaseLongStrings", []int{4, 7}, true},
+		{"über", "Über-Tool", []int{0, 1, 2, 3}, true},
+		{"zz", "charmbracelet/bubbletea", nil, false},
+		{"aet", "tea", nil, false},
+		{"longer", "long", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.text, func(t *testing.T) {
+			_, pos, ok := fuzzyMatch(tt.pattern, tt.text)
+			if ok != tt.ok {
+				t.Fatalf("fuzzyMatch(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.ok)
+			}
+			if !reflect.DeepEqual(pos, tt.want) {
+				t.Errorf("fuzzyMatch(%q, %q) positions = %v, want %v", tt.pattern, tt.text, pos, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstringMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    []int
+		ok      bool
+	}{
+		{"tui", "A TUI framework", []int{2, 3, 4}, true},
+		{"tui f", "A TUI framework", []int{2, 3, 4, 5, 6}, true},
+		{"tf", "A TUI framework", nil, false},
+		{"ß", "Straße", []int{4}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.text, func(t *testing.T) {
+			_, pos, ok := substringMatch(tt.pattern, tt.text)
+			if ok != tt.ok {
+				t.Fatalf("substringMatch(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.ok)
+			}
+			if !reflect.DeepEqual(pos, tt.want) {
+				t.Errorf("substringMatch(%q, %q) positions = %v, want %v", tt.pattern, tt.text, pos, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	// Each pair lists a better match first.
+	tests := []struct {
+		pattern       string
+		better, worse string
+	}{
+		{"tea", "bubbletea", "the extra"},
+		{"st", "gh-stars", "first"},
+		{"ab", "abc", "xab"},
+		{"ls", "LongStrings", "longstrings"},
+		{"abc", "abc", "a-b-c-d-e-f"},
+		{"ac", "a-c", "abbbbbc"},
+	}
+
+	for _, tt := range tests {
+		better, _, ok := fuzzyMatch(tt.pattern, tt.better)
+		if !ok {
+			t.Fatalf("fuzzyMatch(%q, %q) did not match", tt.pattern, tt.better)
+		}
+		worse, _, ok := fuzzyMatch(tt.pattern, tt.worse)
+		if !ok {
+			t.Fatalf("fuzzyMatch(%q, %q) did not match", tt.pattern, tt.worse)
+		}
+		if better <= worse {
+			t.Errorf("%q: score(%q) = %d, not above score(%q) = %d", tt.pattern, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestMatchPositions(t *testing.T) {
+	tests := []struct {
+		input string
+		repo  string
+		want  map[Field][]int
+	}{
+		{"bubble", "bubbletea", map[Field][]int{FieldName: {14, 15, 16, 17, 18, 19}}},
+		{"ripgrep", "ripgrep", map[Field][]int{FieldName: {11, 12, 13, 14, 15, 16, 17}}},
+		{`"little tui"`, "bubbletea", map[Field][]int{FieldDescription: {11, 12, 13, 14, 15, 16, 17, 18, 19, 20}}},
+		{"dashboard", "bubbletea", map[Field][]int{FieldNote: {12, 13, 14, 15, 16, 17, 18, 19, 20}}},
+		{"tea tui", "bubbletea", map[Field][]int{FieldName: {20, 21, 22}, FieldTopics: {0, 1, 2}}},
+		{"tea OR nothing", "bubbletea", map[Field][]int{FieldName: {20, 21, 22}}},
+		{"nothing OR tea", "bubbletea", map[Field][]int{FieldName: {20, 21, 22}}},
+		{"lang:go", "bubbletea", nil},
+		{"tea -nothing", "bubbletea", map[Field][]int{FieldName: {20, 21, 22}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := Parse(tt.input, data.DefaultInactiveAfter)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			repo := testRepos[tt.repo]
+			result, ok := q.Match(repo, testAnnotations[repo.NameWithOwner])
+			if !ok {
+				t.Fatalf("Parse(%q) did not match %s", tt.input, repo.NameWithOwner)
+			}
+			if !reflect.DeepEqual(result.Positions, tt.want) {
+				t.Errorf("Parse(%q) positions = %v, want %v", tt.input, result.Positions, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchScoreOrder(t *testing.T) {
+	q, err := Parse("grep", data.DefaultInactiveAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inName := data.Repo{NameWithOwner: "someone/grep-tools"}
+	inDescription := data.Repo{NameWithOwner: "someone/tools", Description: "wraps grep"}
+
+	name, ok := q.Match(inName, data.Annotation{})
+	if !ok {
+		t.Fatal("no match in name")
+	}
+	description, ok := q.Match(inDescription, data.Annotation{})
+	if !ok {
+		t.Fatal("no match in description")
+	}
+	if name.Score <= description.Score {
+		t.Errorf("name score %d <= description score %d", name.Score, description.Score)
+	}
+}
@@ -0,0 +1,373 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type compareOp int
+
+const (
+	opEq compareOp = iota
+	opGt
+	opGte
+	opLt
+	opLte
+	opRange
+)
+
+func parseQualifier(key, value string) (node, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("missing value for %s:", key)
+	}
+
+	switch key {
+	case "lang", "language":
+		return langNode{lang: strings.ToLower(value)}, nil
+	case "topic":
+		return topicNode{topic: strings.ToLower(value)}, nil
+	case "tag":
+		return tagNode{tag: value}, nil
+	case "note":
+		return noteNode{text: strings.ToLower(value)}, nil
+	case "has":
+		switch strings.ToLower(value) {
+		case "note", "tags", "tag":
+			return hasNode{what: strings.ToLower(value)}, nil
+		}
+		return nil, fmt.Errorf("invalid has value %q (want note or tags)", value)
+	case "owner", "user", "org":
+		return ownerNode{owner: strings.ToLower(value)}, nil
+	case "fork":
+		v, err := parseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fork value %q (want true or false)", value)
+		}
+		return forkNode{fork: v}, nil
+	case "stars":
+		cmp, err := parseNumberCompare(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stars value %q", value)
+		}
+		return starsNode{cmp: cmp}, nil
+	case "is":
+		switch strings.ToLower(value) {
+		case "archived", "disabled", "mirror", "fork", "inactive", "abandoned":
+			return isNode{what: strings.ToLower(value)}, nil
+		}
+		return nil, fmt.Errorf("invalid is value %q (want archived, disabled, mirror, fork, inactive or abandoned)", value)
+	case "starred", "updated", "pushed":
+		cmp, err := parseDateCompare(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q (want a date like 2024-01-31 or an age like 30d)", key, value)
+		}
+		return dateNode{field: key, cmp: cmp}, nil
+	}
+
+	return nil, fmt.Errorf("unknown qualifier %q", key+":")
+}
+
+type langNode struct {
+	lang string
+}
+
+func (n langNode) match(t *target) bool {
+	return strings.EqualFold(t.repo.PrimaryLanguage, n.lang)
+}
+
+type topicNode struct {
+	topic string
+}
+
+func (n topicNode) match(t *target) bool {
+	for _, topic := range t.repo.Topics {
+		if strings.EqualFold(topic, n.topic) {
+			return true
+		}
+	}
+	return false
+}
+
+type tagNode struct {
+	tag string
+}
+
+func (n tagNode) match(t *target) bool {
+	return t.annotation.HasTag(n.tag)
+}
+
+type noteNode struct {
+	text string
+}
+
+func (n noteNode) match(t *target) bool {
+	return strings.Contains(strings.ToLower(t.annotation.Note), n.text)
+}
+
+type hasNode struct {
+	what string
+}
+
+func (n hasNode) match(t *target) bool {
+	if n.what == "note" {
+		return strings.TrimSpace(t.annotation.Note) != ""
+	}
+	return len(t.annotation.Tags) > 0
+}
+
+type ownerNode struct {
+	owner string
+}
+
+func (n ownerNode) match(t *target) bool {
+	owner, _, _ := strings.Cut(t.repo.NameWithOwner, "/")
+	return strings.EqualFold(owner, n.owner)
+}
+
+type forkNode struct {
+	fork bool
+}
+
+func (n forkNode) match(t *target) bool {
+	return t.repo.IsFork == n.fork
+}
+
+// isNode matches repo states. inactive means no pushes within the threshold
+// given to Parse; abandoned is any state that makes a star a cleanup
+// candidate.
+type isNode struct {
+	what string
+}
+
+func (n isNode) match(t *target) bool {
+	switch n.what {
+	case "archived":
+		return t.repo.IsArchived
+	case "disabled":
+		return t.repo.IsDisabled
+	case "mirror":
+		return t.repo.IsMirror
+	case "fork":
+		return t.repo.IsFork
+	case "inactive":
+		return t.repo.Health(t.now, t.inactiveAfter) == data.Inactive
+	}
+	return t.repo.Health(t.now, t.inactiveAfter) != data.Healthy
+}
+
+type starsNode struct {
+	cmp numberCompare
+}
+
+func (n starsNode) match(t *target) bool {
+	return n.cmp.match(t.repo.Stars)
+}
+
+type dateNode struct {
+	field string
+	cmp   dateCompare
+}
+
+func (n dateNode) match(t *target) bool {
+	value := t.repo.UpdatedAt
+	switch n.field {
+	case "starred":
+		value = t.repo.StarredAt
+	case "pushed":
+		value = t.repo.PushedAt
+	}
+	if value.IsZero() {
+		return false
+	}
+	return n.cmp.match(value, t.now)
+}
+
+type numberCompare struct {
+	op        compareOp
+	low, high int
+}
+
+func (c numberCompare) match(v int) bool {
+	switch c.op {
+	case opGt:
+		return v > c.low
+	case opGte:
+		return v >= c.low
+	case opLt:
+		return v < c.low
+	case opLte:
+		return v <= c.low
+	case opRange:
+		return v >= c.low && v <= c.high
+	}
+	return v == c.low
+}
+
+func parseNumberCompare(value string) (numberCompare, error) {
+	if low, high, ok := strings.Cut(value, ".."); ok {
+		lo, err := parseCount(low)
+		if err != nil {
+			return numberCompare{}, err
+		}
+		hi, err := parseCount(high)
+		if err != nil {
+			return numberCompare{}, err
+		}
+		return numberCompare{op: opRange, low: lo, high: hi}, nil
+	}
+
+	op, rest := splitOp(value)
+	n, err := parseCount(rest)
+	if err != nil {
+		return numberCompare{}, err
+	}
+	return numberCompare{op: op, low: n}, nil
+}
+
+func parseCount(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "k"):
+		multiplier = 1_000
+		value = strings.TrimSuffix(value, "k")
+	case strings.HasSuffix(value, "m"):
+		multiplier = 1_000_000
+		value = strings.TrimSuffix(value, "m")
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return int(f * multiplier), nil
+}
+
+// dateCompare compares either against an absolute date or, for relative
+// values like 30d, against the age of the timestamp. The operators read as
+// written, so they point in opposite directions: on a date > means later,
+// on an age it means longer ago. updated:>2024-01-31 is "updated after
+// January 31st" while updated:>30d is "last updated more than 30 days ago".
+type dateCompare struct {
+	op        compareOp
+	low, high time.Time
+	age       time.Duration
+	relative  bool
+}
+
+func (c dateCompare) match(v, now time.Time) bool {
+	if c.relative {
+		age := now.Sub(v)
+		switch c.op {
+		case opGt:
+			return age > c.age
+		case opGte:
+			return age >= c.age
+		case opLt:
+			return age < c.age
+		case opLte:
+			return age <= c.age
+		}
+		return age <= c.age
+	}
+
+	switch c.op {
+	case opGt:
+		return !v.Before(c.low.AddDate(0, 0, 1))
+	case opGte:
+		return !v.Before(c.low)
+	case opLt:
+		return v.Before(c.low)
+	case opLte:
+		return v.Before(c.low.AddDate(0, 0, 1))
+	case opRange:
+		return !v.Before(c.low) && v.Before(c.high.AddDate(0, 0, 1))
+	}
+	return !v.Before(c.low) && v.Before(c.low.AddDate(0, 0, 1))
+}
+
+func parseDateCompare(value string) (dateCompare, error) {
+	if low, high, ok := strings.Cut(value, ".."); ok {
+		lo, err := parseDate(low)
+		if err != nil {
+			return dateCompare{}, err
+		}
+		hi, err := parseDate(high)
+		if err != nil {
+			return dateCompare{}, err
+		}
+		return dateCompare{op: opRange, low: lo, high: hi}, nil
+	}
+
+	op, rest := splitOp(value)
+	if age, err := parseAge(rest); err == nil {
+		return dateCompare{op: op, age: age, relative: true}, nil
+	}
+	date, err := parseDate(rest)
+	if err != nil {
+		return dateCompare{}, err
+	}
+	return dateCompare{op: op, low: date}, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func parseAge(value string) (time.Duration, error) {
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	day := 24 * time.Hour
+	switch value[len(value)-1] {
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'd':
+		return time.Duration(n) * day, nil
+	case 'w':
+		return time.Duration(n) * 7 * day, nil
+	case 'm':
+		return time.Duration(n) * 30 * day, nil
+	case 'y':
+		return time.Duration(n) * 365 * day, nil
+	}
+	return 0, fmt.Errorf("invalid age %q", value)
+}
+
+func splitOp(value string) (compareOp, string) {
+	switch {
+	case strings.HasPrefix(value, ">="):
+		return opGte, value[2:]
+	case strings.HasPrefix(value, "<="):
+		return opLte, value[2:]
+	case strings.HasPrefix(value, ">"):
+		return opGt, value[1:]
+	case strings.HasPrefix(value, "<"):
+		return opLt, value[1:]
+	case strings.HasPrefix(value, "="):
+		return opEq, value[1:]
+	}
+	return opEq, value
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", value)
+}
@@ -0,0 +1,179 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+func TestParseQualifierErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"lang:", "missing value for lang:"},
+		{`topic:" "`, "missing value for topic:"},
+		{"license:mit", `unknown qualifier "license:"`},
+		{"Stars:x", `invalid stars value "x"`},
+		{"has:readme", `invalid has value "readme" (want note or tags)`},
+		{"fork:maybe", `invalid fork value "maybe" (want true or false)`},
+		{"is:popular", `invalid is value "popular" (want archived, disabled, mirror, fork, inactive or abandoned)`},
+		{"stars:-5", `invalid stars value "-5"`},
+		{"stars:>>5", `invalid stars value ">>5"`},
+		{"stars:10..", `invalid stars value "10.."`},
+		{"stars:..10", `invalid stars value "..10"`},
+		{"stars:1..2..3", `invalid stars value "1..2..3"`},
+		{"stars:abck", `invalid stars value "abck"`},
+		{"starred:yesterday", `invalid starred value "yesterday" (want a date like 2024-01-31 or an age like 30d)`},
+		{"pushed:2024-13-01", `invalid pushed value "2024-13-01" (want a date like 2024-01-31 or an age like 30d)`},
+		{"updated:2024..", `invalid updated value "2024.." (want a date like 2024-01-31 or an age like 30d)`},
+		{"updated:>30x", `invalid updated value ">30x" (want a date like 2024-01-31 or an age like 30d)`},
+		{"updated:-3d", `invalid updated value "-3d" (want a date like 2024-01-31 or an age like 30d)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input, data.DefaultInactiveAfter)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want error %q", tt.input, tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumberCompare(t *testing.T) {
+	tests := []struct {
+		value string
+		want  numberCompare
+	}{
+		{"100", numberCompare{op: opEq, low: 100}},
+		{"=100", numberCompare{op: opEq, low: 100}},
+		{">100", numberCompare{op: opGt, low: 100}},
+		{">=1k", numberCompare{op: opGte, low: 1_000}},
+		{"<2.5K", numberCompare{op: opLt, low: 2_500}},
+		{"<=1m", numberCompare{op: opLte, low: 1_000_000}},
+		{"10..500", numberCompare{op: opRange, low: 10, high: 500}},
+		{"1k..2k", numberCompare{op: opRange, low: 1_000, high: 2_000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseNumberCompare(tt.value)
+			if err != nil {
+				t.Fatalf("parseNumberCompare(%q): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseNumberCompare(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberCompareMatch(t *testing.T) {
+	tests := []struct {
+		value string
+		n     int
+		want  bool
+	}{
+		{"100", 100, true},
+		{"100", 101, false},
+		{">100", 100, false},
+		{">=100", 100, true},
+		{"<100", 100, false},
+		{"<=100", 100, true},
+		{"10..20", 10, true},
+		{"10..20", 20, true},
+		{"10..20", 21, false},
+		{"20..10", 15, false},
+	}
+
+	for _, tt := range tests {
+		cmp, err := parseNumberCompare(tt.value)
+		if err != nil {
+			t.Fatalf("parseNumberCompare(%q): %v", tt.value, err)
+		}
+		if got := cmp.match(tt.n); got != tt.want {
+			t.Errorf("stars:%s match(%d) = %v, want %v", tt.value, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestDateCompareMatch(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 18, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		value string
+		v     time.Time
+		want  bool
+	}{
+		{"2024-01-31", day(2024, 1, 31), true},
+		{"2024-01-31", day(2024, 2, 1), false},
+		{">2024-01-31", day(2024, 1, 31), false},
+		{">2024-01-31", day(2024, 2, 1), true},
+		{">=2024-01-31", day(2024, 1, 31), true},
+		{"<2024-01-31", day(2024, 1, 31), false},
+		{"<2024-01-31", day(2024, 1, 30), true},
+		{"<=2024-01-31", day(2024, 1, 31), true},
+		{"<2024", day(2023, 12, 31), true},
+		{"<2024-02", day(2024, 2, 1), false},
+		{"2023..2023-12-31", day(2023, 12, 31), true},
+		{"2023..2023-12-31", day(2024, 1, 1), false},
+		{"2023..2023-12-31", day(2022, 12, 31), false},
+		{"30d", now.AddDate(0, 0, -10), true},
+		{"30d", now.AddDate(0, 0, -40), false},
+		{">30d", now.AddDate(0, 0, -40), true},
+		{">30d", now.AddDate(0, 0, -10), false},
+		{"<2w", now.AddDate(0, 0, -13), true},
+		{"<2w", now.AddDate(0, 0, -15), false},
+		{">=1y", now.AddDate(-1, 0, -1), true},
+		{"<=12h", now.Add(-13 * time.Hour), false},
+		{">6m", now.AddDate(0, -7, 0), true},
+	}
+
+	for _, tt := range tests {
+		cmp, err := parseDateCompare(tt.value)
+		if err != nil {
+			t.Fatalf("parseDateCompare(%q): %v", tt.value, err)
+		}
+		if got := cmp.match(tt.v, now); got != tt.want {
+			t.Errorf("%s match(%s) = %v, want %v", tt.value, tt.v.Format(time.DateTime), got, tt.want)
+		}
+	}
+}
+
+// The same operator points in opposite directions for dates and ages.
+func TestDateCompareDirection(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0)
+	recent := now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		value       string
+		old, recent bool
+	}{
+		{">1y", true, false},
+		{"<1y", false, true},
+		{">2024-01-01", false, true},
+		{"<2024-01-01", true, false},
+	}
+
+	for _, tt := range tests {
+		cmp, err := parseDateCompare(tt.value)
+		if err != nil {
+			t.Fatalf("parseDateCompare(%q): %v", tt.value, err)
+		}
+		if got := cmp.match(old, now); got != tt.old {
+			t.Errorf("%s match(two years ago) = %v, want %v", tt.value, got, tt.old)
+		}
+		if got := cmp.match(recent, now); got != tt.recent {
+			t.Errorf("%s match(yesterday) = %v, want %v", tt.value, got, tt.recent)
+		}
+	}
+}
@@ -0,0 +1,468 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// Query is a parsed search expression. The zero value matches every repo.
+//
+// Grammar:
+//
+//	expr   = and { "OR" and }
+//	and    = unary { unary }
+//	unary  = "-" unary | "(" expr ")" | term
+//	term   = word | "quoted phrase" | key:value | key:"quoted value"
+type Query struct {
+	root          node
+	now           time.Time
+	inactiveAfter time.Duration
+}
+
+// Parse compiles input. inactiveAfter is how long without a push makes a
+// repo match is:inactive.
+func Parse(input string, inactiveAfter time.Duration) (Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return Query{}, err
+	}
+	if len(tokens) == 0 {
+		return Query{}, nil
+	}
+
+	p := parser{tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return Query{}, err
+	}
+	if !p.done() {
+		return Query{}, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	return Query{root: root, now: time.Now(), inactiveAfter: inactiveAfter}, nil
+}
+
+func (q Query) Empty() bool {
+	return q.root == nil
+}
+
+// Match evaluates the query against repo. The result carries a relevance
+// score and the matched rune offsets per field for highlighting.
+func (q Query) Match(repo data.Repo, annotation data.Annotation) (Result, bool) {
+	if q.root == nil {
+		return Result{}, true
+	}
+	t := newTarget(repo, annotation, q.now, q.inactiveAfter)
+	if !q.root.match(t) {
+		return Result{}, false
+	}
+	return t.result, true
+}
+
+type Field int
+
+const (
+	FieldName Field = iota
+	FieldDescription
+	FieldLanguage
+	FieldTopics
+	FieldTags
+	FieldNote
+)
+
+// TopicSeparator and TagSeparator join Repo.Topics and Annotation.Tags into
+// the text that FieldTopics and FieldTags offsets refer to.
+const (
+	TopicSeparator = ", "
+	TagSeparator   = ", "
+)
+
+var fieldWeights = map[Field]int{
+	FieldName:        3,
+	FieldDescription: 1,
+	FieldLanguage:    2,
+	FieldTopics:      2,
+	FieldTags:        3,
+	FieldNote:        1,
+}
+
+type Result struct {
+	Score     int
+	Positions map[Field][]int
+}
+
+func (r *Result) add(field Field, score int, positions []int) {
+	r.Score += score
+	if len(positions) == 0 {
+		return
+	}
+	if r.Positions == nil {
+		r.Positions = make(map[Field][]int)
+	}
+	r.Positions[field] = mergePositions(r.Positions[field], positions)
+}
+
+func mergePositions(a, b []int) []int {
+	seen := make(map[int]struct{}, len(a)+len(b))
+	merged := make([]int, 0, len(a)+len(b))
+	for _, list := range [][]int{a, b} {
+		for _, p := range list {
+			if _, ok := seen[p]; ok {
+				continue
+			}
+			seen[p] = struct{}{}
+			merged = append(merged, p)
+		}
+	}
+	sort.Ints(merged)
+	return merged
+}
+
+type target struct {
+	repo          data.Repo
+	annotation    data.Annotation
+	now           time.Time
+	inactiveAfter time.Duration
+	fields        map[Field]string
+	result        Result
+}
+
+func newTarget(repo data.Repo, annotation data.Annotation, now time.Time, inactiveAfter time.Duration) *target {
+	return &target{
+		repo:          repo,
+		annotation:    annotation,
+		now:           now,
+		inactiveAfter: inactiveAfter,
+		fields: map[Field]string{
+			FieldName:        repo.NameWithOwner,
+			FieldDescription: repo.Description,
+			FieldLanguage:    repo.PrimaryLanguage,
+			FieldTopics:      strings.Join(repo.Topics, TopicSeparator),
+			FieldTags:        strings.Join(annotation.Tags, TagSeparator),
+			FieldNote:        annotation.Note,
+		},
+	}
+}
+
+// scratch returns a copy with an empty result so a subtree can be evaluated
+// without leaking highlights from branches that end up not matching.
+func (t *target) scratch() *target {
+	return &target{repo: t.repo, annotation: t.annotation, now: t.now, inactiveAfter: t.inactiveAfter, fields: t.fields}
+}
+
+type node interface {
+	match(t *target) bool
+}
+
+type andNode struct {
+	children []node
+}
+
+func (n andNode) match(t *target) bool {
+	for _, child := range n.children {
+		if !child.match(t) {
+			return false
+		}
+	}
+	return true
+}
+
+type orNode struct {
+	children []node
+}
+
+func (n orNode) match(t *target) bool {
+	for _, child := range n.children {
+		branch := t.scratch()
+		if child.match(branch) {
+			t.result.Score += branch.result.Score
+			for field, pos := range branch.result.Positions {
+				t.result.add(field, 0, pos)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+type notNode struct {
+	child node
+}
+
+func (n notNode) match(t *target) bool {
+	return !n.child.match(t.scratch())
+}
+
+type textNode struct {
+	text   string
+	phrase bool
+}
+
+func (n textNode) match(t *target) bool {
+	bestField := Field(-1)
+	bestScore := 0
+	var bestPos []int
+
+	for field, text := range t.fields {
+		if text == "" {
+			continue
+		}
+		var score int
+		var pos []int
+		var ok bool
+		if n.phrase {
+			score, pos, ok = substringMatch(n.text, text)
+		} else {
+			score, pos, ok = fuzzyMatch(n.text, text)
+		}
+		if !ok || (field != FieldName && !tightEnough(n.text, pos)) {
+			continue
+		}
+		score *= fieldWeights[field]
+		if field == FieldName && inRepoName(text, pos) {
+			score += scoreBoundary * len(pos)
+		}
+		if bestField < 0 || score > bestScore || (score == bestScore && field < bestField) {
+			bestField, bestScore, bestPos = field, score, pos
+		}
+	}
+
+	if bestField < 0 {
+		return false
+	}
+	t.result.add(bestField, bestScore, bestPos)
+	return true
+}
+
+// tightEnough rejects scattered matches in long free-text fields, where
+// almost any short pattern would otherwise match somewhere.
+func tightEnough(pattern string, pos []int) bool {
+	if len(pos) == 0 {
+		return true
+	}
+	span := pos[len(pos)-1] - pos[0] + 1
+	return span <= 2*len([]rune(pattern))
+}
+
+func inRepoName(nameWithOwner string, pos []int) bool {
+	slash := strings.IndexRune(nameWithOwner, '/')
+	if slash < 0 || len(pos) == 0 {
+		return false
+	}
+	return pos[0] > len([]rune(nameWithOwner[:slash]))
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokPhrase
+	tokQualifier
+	tokNot
+	tokOr
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	key   string
+	value string
+}
+
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := []token{}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")"})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, token{kind: tokNot, text: "-"})
+			i++
+		case r == '"':
+			end := indexRune(runes, i+1, '"')
+			if end < 0 {
+				return nil, fmt.Errorf("missing closing quote")
+			}
+			text := string(runes[i+1 : end])
+			tokens = append(tokens, token{kind: tokPhrase, text: text})
+			i = end + 1
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				// A colon followed by a slash is a URL scheme, not a qualifier.
+				if runes[i] == ':' && isQualifierKey(runes[start:i]) && (i+1 >= len(runes) || runes[i+1] != '/') {
+					break
+				}
+				i++
+			}
+			if i < len(runes) && runes[i] == ':' {
+				key := strings.ToLower(string(runes[start:i]))
+				i++
+				var value string
+				if i < len(runes) && runes[i] == '"' {
+					end := indexRune(runes, i+1, '"')
+					if end < 0 {
+						return nil, fmt.Errorf("missing closing quote for %s:", key)
+					}
+					value = string(runes[i+1 : end])
+					i = end + 1
+				} else {
+					valueStart := i
+					for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != ')' {
+						i++
+					}
+					value = string(runes[valueStart:i])
+				}
+				tokens = append(tokens, token{
+					kind:  tokQualifier,
+					text:  string(runes[start:i]),
+					key:   key,
+					value: value,
+				})
+				continue
+			}
+			text := string(runes[start:i])
+			if text == "OR" {
+				tokens = append(tokens, token{kind: tokOr, text: text})
+				continue
+			}
+			tokens = append(tokens, token{kind: tokWord, text: text})
+		}
+	}
+
+	return tokens, nil
+}
+
+func indexRune(runes []rune, from int, target rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+func isQualifierKey(runes []rune) bool {
+	if len(runes) == 0 {
+		return false
+	}
+	for _, r := range runes {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) parseExpr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []node{first}
+	for !p.done() && p.peek().kind == tokOr {
+		p.pos++
+		if p.done() {
+			return nil, fmt.Errorf("missing term after OR")
+		}
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return orNode{children: children}, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	children := []node{}
+	for !p.done() {
+		kind := p.peek().kind
+		if kind == tokOr || kind == tokRParen {
+			break
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	switch len(children) {
+	case 0:
+		if p.done() {
+			return nil, fmt.Errorf("empty expression")
+		}
+		return nil, fmt.Errorf("unexpected %q", p.peek().text)
+	case 1:
+		return children[0], nil
+	}
+	return andNode{children: children}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+	p.pos++
+
+	switch tok.kind {
+	case tokNot:
+		if p.done() {
+			return nil, fmt.Errorf("missing term after -")
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{child: child}, nil
+	case tokLParen:
+		if p.done() {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	case tokWord:
+		return textNode{text: strings.ToLower(tok.text)}, nil
+	case tokPhrase:
+		return textNode{text: strings.ToLower(tok.text), phrase: true}, nil
+	case tokQualifier:
+		return parseQualifier(tok.key, tok.value)
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
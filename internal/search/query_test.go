@@ -0,0 +1,161 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+var testRepos = map[string]data.Repo{
+	"bubbletea": {
+		NameWithOwner:   "charmbracelet/bubbletea",
+		Description:     "A powerful little TUI framework",
+		PrimaryLanguage: "Go",
+		Stars:           28_000,
+		Topics:          []string{"tui", "terminal", "elm-architecture"},
+		PushedAt:        time.Now().AddDate(0, 0, -3),
+		StarredAt:       time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC),
+	},
+	"ripgrep": {
+		NameWithOwner:   "BurntSushi/ripgrep",
+		Description:     "ripgrep recursively searches directories for a regex pattern",
+		PrimaryLanguage: "Rust",
+		Stars:           48_000,
+		Topics:          []string{"search", "grep", "cli"},
+		PushedAt:        time.Now().AddDate(0, -1, 0),
+		StarredAt:       time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	},
+	"oldfork": {
+		NameWithOwner:   "someone/old-parser",
+		Description:     "A parser fork nobody maintains",
+		PrimaryLanguage: "Python",
+		Stars:           12,
+		IsFork:          true,
+		PushedAt:        time.Now().AddDate(-3, 0, 0),
+		StarredAt:       time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC),
+	},
+	"archived": {
+		NameWithOwner:   "acme/legacy-tool",
+		Description:     "Deprecated tooling",
+		PrimaryLanguage: "Go",
+		Stars:           900,
+		IsArchived:      true,
+		PushedAt:        time.Now().AddDate(0, -2, 0),
+		StarredAt:       time.Date(2022, 12, 31, 23, 0, 0, 0, time.UTC),
+	},
+}
+
+var testAnnotations = data.Annotations{
+	"charmbracelet/bubbletea": {Note: "use for the dashboard rewrite", Tags: []string{"ui", "Favorites"}},
+	"BurntSushi/ripgrep":      {Tags: []string{"cli"}},
+}
+
+func matching(t *testing.T, input string) []string {
+	t.Helper()
+	q, err := Parse(input, data.DefaultInactiveAfter)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	var names []string
+	for _, name := range []string{"bubbletea", "ripgrep", "oldfork", "archived"} {
+		repo := testRepos[name]
+		if _, ok := q.Match(repo, testAnnotations[repo.NameWithOwner]); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestParseMatch(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{"bubbletea", "ripgrep", "oldfork", "archived"}},
+		{"   ", []string{"bubbletea", "ripgrep", "oldfork", "archived"}},
+		{"bubble", []string{"bubbletea"}},
+		{"BUBBLE", []string{"bubbletea"}},
+		{"parser", []string{"oldfork"}},
+		{"tui framework", []string{"bubbletea"}},
+		{"tui OR grep", []string{"bubbletea", "ripgrep"}},
+		{"go tui OR rust", []string{"bubbletea", "ripgrep"}},
+		{"-lang:go", []string{"ripgrep", "oldfork"}},
+		{"lang:go -tui", []string{"archived"}},
+		{"-(lang:go OR lang:rust)", []string{"oldfork"}},
+		{"(lang:go OR lang:rust) stars:>1k", []string{"bubbletea", "ripgrep"}},
+		{"--lang:rust", []string{"ripgrep"}},
+		{`"tui framework"`, []string{"bubbletea"}},
+		{`"framework tui"`, nil},
+		{"language:Rust", []string{"ripgrep"}},
+		{"topic:TUI", []string{"bubbletea"}},
+		{"topic:tu", nil},
+		{"tag:favorites", []string{"bubbletea"}},
+		{`note:"dashboard rewrite"`, []string{"bubbletea"}},
+		{"has:note", []string{"bubbletea"}},
+		{"has:tags", []string{"bubbletea", "ripgrep"}},
+		{"owner:burntsushi", []string{"ripgrep"}},
+		{"org:acme user:acme", []string{"archived"}},
+		{"fork:true", []string{"oldfork"}},
+		{"fork:no", []string{"bubbletea", "ripgrep", "archived"}},
+		{"stars:900", []string{"archived"}},
+		{"stars:>=900", []string{"bubbletea", "ripgrep", "archived"}},
+		{"stars:<1k", []string{"oldfork", "archived"}},
+		{"stars:10..1000", []string{"oldfork", "archived"}},
+		{"stars:>30k", []string{"ripgrep"}},
+		{"stars:1.5m", nil},
+		{"is:archived", []string{"archived"}},
+		{"is:fork", []string{"oldfork"}},
+		{"is:inactive", []string{"oldfork"}},
+		{"is:abandoned", []string{"oldfork", "archived"}},
+		{"starred:2023-05-10", []string{"bubbletea"}},
+		{"starred:2022-12-31", []string{"archived"}},
+		{"starred:>2022-12-31", []string{"bubbletea"}},
+		{"starred:>=2022-12-31", []string{"bubbletea", "archived"}},
+		{"starred:<2021", []string{"oldfork"}},
+		{"starred:<=2021-01-02", []string{"ripgrep", "oldfork"}},
+		{"starred:2020..2022-06", []string{"ripgrep"}},
+		{"pushed:<1w", []string{"bubbletea"}},
+		{"pushed:>1y", []string{"oldfork"}},
+		{"pushed:7d", []string{"bubbletea"}},
+		{"http://example.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := matching(t, tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) matched %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"unterminated`, "missing closing quote"},
+		{`note:"unterminated`, "missing closing quote for note:"},
+		{"(tui", "missing closing parenthesis"},
+		{"(", "missing closing parenthesis"},
+		{"tui)", `unexpected ")"`},
+		{"()", `unexpected ")"`},
+		{"tui OR", "missing term after OR"},
+		{"OR tui", `unexpected "OR"`},
+		{"tui OR OR grep", `unexpected "OR"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input, data.DefaultInactiveAfter)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want error %q", tt.input, tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
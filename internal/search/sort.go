@@ -0,0 +1,93 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// SortModes lists the orderings in the order the TUI cycles through them.
+var SortModes = []string{"default", "stars", "name", "updated", "health", "relevance"}
+
+func ValidateSortMode(mode string) error {
+	for _, m := range SortModes {
+		if m == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown sort mode %q (want one of %s)", mode, strings.Join(SortModes, ", "))
+}
+
+func NextSortMode(mode string) string {
+	for i, m := range SortModes {
+		if m == mode {
+			return SortModes[(i+1)%len(SortModes)]
+		}
+	}
+	return SortModes[0]
+}
+
+// Filter returns the indices of repos matching q, plus the match results of
+// those that carry a score or highlights. keep, when set, narrows the
+// candidates before the query runs.
+func Filter(repos []data.Repo, q Query, annotations data.Annotations, keep func(data.Repo) bool) ([]int, map[int]Result) {
+	indices := make([]int, 0, len(repos))
+	results := make(map[int]Result)
+
+	for i, repo := range repos {
+		if keep != nil && !keep(repo) {
+			continue
+		}
+		result, ok := q.Match(repo, annotations[repo.NameWithOwner])
+		if !ok {
+			continue
+		}
+		indices = append(indices, i)
+		if result.Score != 0 || len(result.Positions) > 0 {
+			results[i] = result
+		}
+	}
+
+	return indices, results
+}
+
+// Sort orders indices into repos by mode. "default" is most recently
+// starred first; "relevance" uses the match scores from Filter; "health"
+// puts disabled and archived repos first, then the longest without a push,
+// counting repos as inactive after inactiveAfter.
+func Sort(repos []data.Repo, indices []int, mode string, results map[int]Result, inactiveAfter time.Duration) {
+	now := time.Now()
+	sort.SliceStable(indices, func(i, j int) bool {
+		a := repos[indices[i]]
+		b := repos[indices[j]]
+		switch mode {
+		case "relevance":
+			sa, sb := results[indices[i]].Score, results[indices[j]].Score
+			if sa != sb {
+				return sa > sb
+			}
+			return a.StarredAt.After(b.StarredAt)
+		case "default":
+			return a.StarredAt.After(b.StarredAt)
+		case "stars":
+			return a.Stars > b.Stars
+		case "name":
+			return strings.ToLower(a.NameWithOwner) < strings.ToLower(b.NameWithOwner)
+		case "updated":
+			return a.UpdatedAt.After(b.UpdatedAt)
+		case "health":
+			ha, hb := a.Health(now, inactiveAfter), b.Health(now, inactiveAfter)
+			if ha != hb {
+				return ha > hb
+			}
+			if a.PushedAt.IsZero() != b.PushedAt.IsZero() {
+				return b.PushedAt.IsZero()
+			}
+			return a.PushedAt.Before(b.PushedAt)
+		}
+		return false
+	})
+}
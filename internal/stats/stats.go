@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+const (
+	topN        = 10
+	monthsShown = 24
+)
+
+type Count struct {
+	Name  string
+	Count int
+}
+
+type Month struct {
+	Start time.Time
+	Count int
+}
+
+type Summary struct {
+	Total     int
+	Forks     int
+	Archived  int
+	Stale     int
+	Months    []Month
+	Languages []Count
+	Topics    []Count
+	Owners    []Count
+	StarRange []Count
+}
+
+var starBuckets = []struct {
+	label string
+	below int
+}{
+	{"< 10", 10},
+	{"10–99", 100},
+	{"100–999", 1_000},
+	{"1k–9.9k", 10_000},
+	{"10k–99k", 100_000},
+	{"100k+", -1},
+}
+
+// Compute summarizes repos. Months covers the last two years up to now,
+// including months without stars, so it can be drawn as a time series.
+// Repos count as stale once they went longer than inactiveAfter without a
+// push, the same rule as is:inactive.
+func Compute(repos []data.Repo, now time.Time, inactiveAfter time.Duration) Summary {
+	summary := Summary{Total: len(repos)}
+
+	languages := map[string]int{}
+	topics := map[string]int{}
+	owners := map[string]int{}
+	buckets := make([]int, len(starBuckets))
+
+	current := monthStart(now)
+	first := current.AddDate(0, -(monthsShown - 1), 0)
+	summary.Months = make([]Month, monthsShown)
+	for i := range summary.Months {
+		summary.Months[i].Start = first.AddDate(0, i, 0)
+	}
+
+	for _, repo := range repos {
+		if repo.IsFork {
+			summary.Forks++
+		}
+		if repo.IsArchived || repo.IsDisabled {
+			summary.Archived++
+		}
+		if repo.IsInactive(now, inactiveAfter) {
+			summary.Stale++
+		}
+
+		if !repo.StarredAt.IsZero() {
+			starred := monthStart(repo.StarredAt)
+			if !starred.Before(first) && !starred.After(current) {
+				i := (starred.Year()-first.Year())*12 + int(starred.Month()-first.Month())
+				summary.Months[i].Count++
+			}
+		}
+
+		language := repo.PrimaryLanguage
+		if language == "" {
+			language = "none"
+		}
+		languages[language]++
+		for _, topic := range repo.Topics {
+			topics[topic]++
+		}
+		owner, _, _ := strings.Cut(repo.NameWithOwner, "/")
+		owners[owner]++
+
+		for i, bucket := range starBuckets {
+			if bucket.below < 0 || repo.Stars < bucket.below {
+				buckets[i]++
+				break
+			}
+		}
+	}
+
+	summary.Languages = top(languages)
+	summary.Topics = top(topics)
+	summary.Owners = top(owners)
+	for i, bucket := range starBuckets {
+		summary.StarRange = append(summary.StarRange, Count{Name: bucket.label, Count: buckets[i]})
+	}
+	return summary
+}
+
+func top(counts map[string]int) []Count {
+	list := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, Count{Name: name, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	if len(list) > topN {
+		list = list[:topN]
+	}
+	return list
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+func TestComputeStale(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	repos := []data.Repo{
+		{NameWithOwner: "a/recent", PushedAt: now.AddDate(0, -1, 0)},
+		{NameWithOwner: "a/old", PushedAt: now.AddDate(-2, 0, 0)},
+		// Without a known push, UpdatedAt does not make a repo stale.
+		{NameWithOwner: "a/unknown", UpdatedAt: now.AddDate(-3, 0, 0)},
+	}
+
+	tests := []struct {
+		after time.Duration
+		want  int
+	}{
+		{data.DefaultInactiveAfter, 1},
+		{7 * 24 * time.Hour, 2},
+		{3 * data.DefaultInactiveAfter, 0},
+	}
+
+	for _, tt := range tests {
+		summary := Compute(repos, now, tt.after)
+		if summary.Stale != tt.want {
+			t.Errorf("Compute(after %s).Stale = %d, want %d", tt.after, summary.Stale, tt.want)
+		}
+		for _, repo := range repos {
+			stale := repo.Health(now, tt.after) == data.Inactive
+			if stale != repo.IsInactive(now, tt.after) {
+				t.Errorf("%s: Health and IsInactive disagree after %s", repo.NameWithOwner, tt.after)
+			}
+		}
+	}
+}
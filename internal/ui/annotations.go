@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type editorKind int
+
+const (
+	editNote editorKind = iota
+	editTags
+	editLogin
+)
+
+// annotationEditor is the modal used to edit a repo's local note or tags. It
+// also asks for the login whose stars to browse.
+type annotationEditor struct {
+	repos []string
+	kind  editorKind
+	note  textarea.Model
+	input textinput.Model
+}
+
+func (m *Model) annotation(name string) data.Annotation {
+	return m.annotations[name]
+}
+
+// updateAnnotations applies update and saves the result, picking up what
+// other instances saved since. Without an annotations file, or when saving
+// fails, the change only lasts for this session.
+func (m *Model) updateAnnotations(update func(data.Annotations)) error {
+	if m.annotationsPath == "" {
+		update(m.annotations)
+		return nil
+	}
+	saved, err := data.UpdateAnnotations(m.annotationsPath, update)
+	if err != nil {
+		update(m.annotations)
+		return err
+	}
+	m.annotations = saved
+	return nil
+}
+
+func (m *Model) openNoteEditor(repo data.Repo) tea.Cmd {
+	ta := textarea.New()
+	ta.Placeholder = "Why did you star this?"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 4000
+	ta.SetValue(m.annotation(repo.NameWithOwner).Note)
+	m.editor = &annotationEditor{repos: []string{repo.NameWithOwner}, kind: editNote, note: ta}
+	m.resizeEditor()
+	return m.editor.note.Focus()
+}
+
+func (m *Model) openTagsEditor(repos []data.Repo) tea.Cmd {
+	if len(repos) == 0 {
+		return nil
+	}
+	ti := m.newLineInput("Tags: ", "comma or space separated")
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.NameWithOwner)
+	}
+	if len(repos) == 1 {
+		ti.SetValue(strings.Join(m.annotation(repos[0].NameWithOwner).Tags, ", "))
+	}
+	m.editor = &annotationEditor{repos: names, kind: editTags, input: ti}
+	m.resizeEditor()
+	return m.editor.input.Focus()
+}
+
+func (m *Model) newLineInput(prompt, placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = prompt
+	ti.Placeholder = placeholder
+	ti.CharLimit = 400
+	ti.PromptStyle = m.styles.SearchPrompt
+	ti.TextStyle = m.styles.SearchText
+	return ti
+}
+
+func (m *Model) resizeEditor() {
+	if m.editor == nil {
+		return
+	}
+	width := m.panelContentWidth(m.listWidth)
+	if m.previewWidth > 0 {
+		width = m.panelContentWidth(m.previewWidth)
+	}
+	height := m.panelContentHeight(m.listHeight())
+	m.editor.note.SetWidth(max(10, width))
+	m.editor.note.SetHeight(max(3, height-4))
+	m.editor.input.Width = max(10, width-len(m.editor.input.Prompt)-1)
+}
+
+func (m Model) updateEditor(msg tea.Msg) (Model, tea.Cmd) {
+	editor := *m.editor
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "esc":
+			m.editor = nil
+			m.status = "edit cancelled"
+			m.statusIsError = false
+			return m, nil
+		case "ctrl+s":
+			return m.saveEditor(editor)
+		case "enter":
+			if editor.kind != editNote {
+				return m.saveEditor(editor)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if editor.kind == editNote {
+		editor.note, cmd = editor.note.Update(msg)
+	} else {
+		editor.input, cmd = editor.input.Update(msg)
+	}
+	m.editor = &editor
+	return m, cmd
+}
+
+func (m Model) saveEditor(editor annotationEditor) (Model, tea.Cmd) {
+	m.editor = nil
+	if editor.kind == editLogin {
+		return m.resolveOwner(editor.input.Value())
+	}
+	note := strings.TrimSpace(editor.note.Value())
+	tags := data.ParseTags(editor.input.Value())
+	err := m.updateAnnotations(func(annotations data.Annotations) {
+		// Only the edited field changes, on top of what is saved now.
+		for _, name := range editor.repos {
+			annotation := annotations[name]
+			switch editor.kind {
+			case editNote:
+				annotation.Note = note
+			case editTags:
+				if len(editor.repos) > 1 {
+					annotation.Tags = data.ParseTags(strings.Join(append(annotation.Tags, tags...), ","))
+				} else {
+					annotation.Tags = tags
+				}
+			}
+			annotations.Set(name, annotation)
+		}
+	})
+
+	m.applyFilter()
+	if m.annotationsPath == "" {
+		// No cache dir, or the annotations file failed to load at startup.
+		m.status = "not saved: annotations only last for this session"
+		m.statusIsError = true
+		return m, nil
+	}
+	if err != nil {
+		m.status = fmt.Sprintf("annotations save failed: %v", err)
+		m.statusIsError = true
+		return m, nil
+	}
+	m.status = "saved"
+	if editor.kind == editTags && len(editor.repos) > 1 {
+		m.status = fmt.Sprintf("tagged %d repos", len(editor.repos))
+	}
+	m.statusIsError = false
+	return m, nil
+}
+
+func (m Model) renderEditor(height, width int) string {
+	editor := m.editor
+	title := "Note"
+	hint := "ctrl+s save · esc cancel"
+	body := editor.note.View()
+	switch editor.kind {
+	case editTags:
+		title = "Tags"
+		hint = "↵ save · esc cancel"
+		body = editor.input.View()
+		if len(editor.repos) > 1 {
+			hint = "↵ add to all · esc cancel"
+		}
+	case editLogin:
+		title = "Browse stars"
+		hint = "↵ load · esc cancel"
+		body = editor.input.View()
+	}
+	switch {
+	case len(editor.repos) == 1:
+		title += " · " + editor.repos[0]
+	case len(editor.repos) > 1:
+		title += fmt.Sprintf(" · %d repos", len(editor.repos))
+	}
+
+	lines := []string{m.styles.PanelTitle.Render(truncate(title, width)), ""}
+	lines = append(lines, strings.Split(body, "\n")...)
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, m.styles.Muted.Render(truncate(hint, width)))
+
+	for i := range lines {
+		lines[i] = padRight(lines[i], width)
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return strings.Join(lines, "\n")
+}
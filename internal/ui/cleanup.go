@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// cleanupQuery matches stars that are candidates for unstarring. It goes
+// into the search box so it can be narrowed, e.g. to "pushed:>3y".
+const cleanupQuery = "is:abandoned"
+
+// startCleanup lists disabled, archived and inactive repos worst first and
+// selects them all, so deselecting the keepers and unstarring is all that is
+// left to do.
+func (m *Model) startCleanup() {
+	m.searchInput.SetValue(cleanupQuery)
+	m.searchInput.CursorEnd()
+	m.sortMode = "health"
+	m.cursor = 0
+	m.offset = 0
+	m.previewOffset = 0
+	m.applyFilter()
+	m.clearSelection()
+
+	if len(m.filtered) == 0 {
+		m.status = "no cleanup candidates"
+		m.statusIsError = false
+		return
+	}
+	m.selectAllFiltered()
+	m.status = fmt.Sprintf("%d cleanup candidates selected · %s to keep one, %s to unstar the rest",
+		len(m.selection), m.keys.Help(actionSelect), m.keys.Help(actionUnstar))
+}
+
+// healthBadges describes the repo states worth flagging next to its name.
+func healthBadges(repo data.Repo, health data.Health) []string {
+	badges := []string{}
+	switch health {
+	case data.Disabled:
+		badges = append(badges, "disabled ⛔")
+	case data.Archived:
+		badges = append(badges, "archived 📦")
+	case data.Inactive:
+		badges = append(badges, "inactive 💤")
+	}
+	if repo.IsMirror {
+		badges = append(badges, "mirror 🪞")
+	}
+	return badges
+}
+
+// healthWarning explains in the preview why a repo is a cleanup candidate.
+func healthWarning(repo data.Repo, now time.Time, inactiveAfter time.Duration) string {
+	switch repo.Health(now, inactiveAfter) {
+	case data.Disabled:
+		return "⛔ Disabled by GitHub"
+	case data.Archived:
+		return "📦 Archived: read-only and no longer maintained"
+	case data.Inactive:
+		return fmt.Sprintf("💤 No pushes in %s", humanizeAge(now.Sub(repo.PushedAt)))
+	}
+	return ""
+}
+
+// humanizeThreshold names a configured duration, saying "a year" for the
+// default rather than "12 months".
+func humanizeThreshold(d time.Duration) string {
+	if int(d.Hours()/24) == 365 {
+		return "a year"
+	}
+	return humanizeAge(d)
+}
+
+func humanizeAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days >= 730:
+		return fmt.Sprintf("%d years", days/365)
+	case days >= 60:
+		return fmt.Sprintf("%d months", days/30)
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/workspace"
+)
+
+type cloneProgressMsg struct {
+	name   string
+	line   string
+	events <-chan tea.Msg
+}
+
+type cloneDoneMsg struct {
+	name string
+	path string
+	err  error
+}
+
+type clonesScannedMsg struct {
+	cloned map[string]bool
+}
+
+func (m Model) startClone(repo data.Repo) (Model, tea.Cmd) {
+	path, err := workspace.Path(m.cloneLayout, repo)
+	if err != nil {
+		m.status = fmt.Sprintf("clone failed: %v", err)
+		m.statusIsError = true
+		return m, nil
+	}
+	if workspace.IsCloned(path) {
+		m.cloned[repo.NameWithOwner] = true
+		m.status = "already cloned at " + path
+		m.statusIsError = false
+		return m, nil
+	}
+	if m.cloning[repo.NameWithOwner] {
+		return m, nil
+	}
+
+	m.cloning[repo.NameWithOwner] = true
+	m.status = "cloning " + repo.NameWithOwner
+	m.statusIsError = false
+	return m, cloneCmd(m.ctx, m.jobs, repo.NameWithOwner, workspace.CloneURL(repo, m.cloneProtocol), path)
+}
+
+func (m Model) handleCloneProgress(msg cloneProgressMsg) (Model, tea.Cmd) {
+	m.status = fmt.Sprintf("cloning %s: %s", msg.name, msg.line)
+	m.statusIsError = false
+	return m, waitForClone(msg.events)
+}
+
+func (m Model) handleCloneDone(msg cloneDoneMsg) (Model, tea.Cmd) {
+	delete(m.cloning, msg.name)
+	if msg.err != nil {
+		m.status = fmt.Sprintf("clone %s failed: %v", msg.name, msg.err)
+		m.statusIsError = true
+		return m, nil
+	}
+	m.cloned[msg.name] = true
+	m.status = "cloned to " + msg.path
+	m.statusIsError = false
+	return m, nil
+}
+
+// cloneCmd runs git in the background and feeds its progress back through
+// events, one message at a time. Cancelling ctx stops git; jobs tracks the
+// clone until its partial checkout is removed.
+func cloneCmd(ctx context.Context, jobs *sync.WaitGroup, name, cloneURL, path string) tea.Cmd {
+	events := make(chan tea.Msg, 16)
+	jobs.Add(1)
+	go func() {
+		err := workspace.Clone(ctx, cloneURL, path, func(line string) {
+			// Never let a slow UI stall git. When it falls behind, the oldest
+			// line makes room so the latest progress wins. This is the only
+			// sender, so the second send cannot block.
+			msg := cloneProgressMsg{name: name, line: line, events: events}
+			select {
+			case events <- msg:
+			default:
+				select {
+				case <-events:
+				default:
+				}
+				events <- msg
+			}
+		})
+		// Done before reporting: once the program has quit nobody reads events.
+		jobs.Done()
+		events <- cloneDoneMsg{name: name, path: path, err: err}
+		close(events)
+	}()
+	return waitForClone(events)
+}
+
+func waitForClone(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+func scanClonesCmd(layout string, repos []data.Repo) tea.Cmd {
+	if len(repos) == 0 {
+		return nil
+	}
+	repos = append([]data.Repo(nil), repos...)
+	return func() tea.Msg {
+		cloned := make(map[string]bool)
+		for _, repo := range repos {
+			path, err := workspace.Path(layout, repo)
+			if err == nil && workspace.IsCloned(path) {
+				cloned[repo.NameWithOwner] = true
+			}
+		}
+		return clonesScannedMsg{cloned: cloned}
+	}
+}
@@ -1,5 +1,7 @@
 package ui
 
+import "time"
+
 const (
 	footerHeight     = 1
 	panelPaddingY    = 0
@@ -9,4 +11,15 @@ const (
 	searchBoxPadding = 1
 	listRowHeight    = 3
 	panelGap         = 2
+	facetPanelWidth  = 30
+	facetLimit       = 8
+)
+
+const (
+	readmeTTL       = 7 * 24 * time.Hour
+	detailsTTL      = 24 * time.Hour
+	readmeDebounce  = 150 * time.Millisecond
+	undoWindow      = 10 * time.Second
+	hydrateRetry    = 30 * time.Minute
+	mutationTimeout = 30 * time.Second
 )
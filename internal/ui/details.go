@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// detailsLines renders the lazily fetched metadata of a repo. It stays empty
+// while loading so the preview does not jump around.
+func (m Model) detailsLines(name string, width int) []string {
+	state, ok := m.details[name]
+	if !ok || state.loading {
+		return nil
+	}
+	if state.err != nil {
+		return m.mutedLines([]string{"details unavailable: " + state.err.Error()}, width)
+	}
+	details := state.details
+
+	counts := []string{
+		fmt.Sprintf("🍴 %s forks", compactCount(details.Forks)),
+		fmt.Sprintf("👀 %s watching", compactCount(details.Watchers)),
+		fmt.Sprintf("🐛 %s issues", compactCount(details.OpenIssues)),
+		fmt.Sprintf("🔀 %s PRs", compactCount(details.OpenPRs)),
+	}
+
+	facts := []string{}
+	if details.License != "" {
+		facts = append(facts, "⚖ "+details.License)
+	}
+	if details.LatestRelease != "" {
+		release := "🚀 " + details.LatestRelease
+		if !details.ReleasedAt.IsZero() {
+			release += " (" + details.ReleasedAt.Format("2006-01-02") + ")"
+		}
+		facts = append(facts, release)
+	}
+	if details.DefaultBranch != "" {
+		facts = append(facts, "🌿 "+details.DefaultBranch)
+	}
+	if details.DiskUsageKB > 0 {
+		facts = append(facts, "💾 "+formatSize(details.DiskUsageKB))
+	}
+
+	text := []string{strings.Join(counts, "  ")}
+	if len(facts) > 0 {
+		text = append(text, strings.Join(facts, "  "))
+	}
+	if details.Homepage != "" {
+		text = append(text, "🔗 "+details.Homepage)
+	}
+	return m.mutedLines(text, width)
+}
+
+func (m Model) mutedLines(text []string, width int) []string {
+	lines := []string{}
+	for _, line := range wrapLines(text, width) {
+		lines = append(lines, m.styles.Muted.Render(line))
+	}
+	return lines
+}
+
+func compactCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000_000), ".0") + "m"
+	case n >= 1_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000), ".0") + "k"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func formatSize(kb int) string {
+	switch {
+	case kb >= 1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(kb)/(1024*1024))
+	case kb >= 1024:
+		return fmt.Sprintf("%.1f MB", float64(kb)/1024)
+	}
+	return fmt.Sprintf("%d KB", kb)
+}
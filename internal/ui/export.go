@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/export"
+	"github.com/viniciussoares/github-stars-tui/internal/search"
+)
+
+var exportFormatLabels = map[string]string{
+	"markdown": "Markdown",
+	"csv":      "CSV",
+	"html":     "HTML page",
+	"opml":     "OPML (release feeds)",
+}
+
+func (m *Model) openExport() {
+	items := make([]pickerItem, 0, len(export.Formats))
+	for _, format := range export.Formats {
+		items = append(items, pickerItem{label: exportFormatLabels[format], detail: export.Extension(format), value: format})
+	}
+	m.picker = &picker{
+		title: "Export",
+		items: items,
+		onSubmit: func(m Model, p picker) (Model, tea.Cmd) {
+			m.openExportScope(p.selected().value)
+			return m, nil
+		},
+	}
+}
+
+func (m *Model) openExportScope(format string) {
+	items := []pickerItem{
+		{label: "Current view", detail: fmt.Sprintf("%d", len(m.filtered)), value: "view"},
+		{label: "All stars", detail: fmt.Sprintf("%d", len(m.repos)), value: "all"},
+	}
+	if len(m.selection) > 0 {
+		items = append([]pickerItem{{label: "Selection", detail: fmt.Sprintf("%d", len(m.selection)), value: "selection"}}, items...)
+	}
+	m.picker = &picker{
+		title: "Export " + exportFormatLabels[format],
+		items: items,
+		onSubmit: func(m Model, p picker) (Model, tea.Cmd) {
+			var repos []data.Repo
+			switch p.selected().value {
+			case "selection":
+				repos = m.targetRepos()
+			case "all":
+				repos = m.exportRepos(true)
+			default:
+				repos = m.exportRepos(false)
+			}
+			if len(repos) == 0 {
+				m.status = "nothing to export"
+				m.statusIsError = false
+				return m, nil
+			}
+			m.status = fmt.Sprintf("exporting %d repos...", len(repos))
+			m.statusIsError = false
+			opts := export.Options{Format: format, Title: "GitHub Stars", Annotations: m.annotations}
+			return m, exportCmd(repos, opts)
+		},
+	}
+}
+
+// exportRepos returns the visible rows in their on-screen order, or every
+// cached repo ordered by the current sort mode.
+func (m Model) exportRepos(all bool) []data.Repo {
+	indices := m.filtered
+	if all {
+		indices = make([]int, len(m.repos))
+		for i := range indices {
+			indices[i] = i
+		}
+		mode := m.sortMode
+		if mode == "relevance" {
+			mode = "default"
+		}
+		search.Sort(m.repos, indices, mode, nil, m.inactiveAfter)
+	}
+
+	repos := make([]data.Repo, 0, len(indices))
+	for _, idx := range indices {
+		if idx >= 0 && idx < len(m.repos) {
+			repos = append(repos, m.repos[idx])
+		}
+	}
+	return repos
+}
+
+func exportCmd(repos []data.Repo, opts export.Options) tea.Cmd {
+	return func() tea.Msg {
+		path, err := writeExport(repos, opts)
+		if err != nil {
+			return statusMsg{text: fmt.Sprintf("export failed: %v", err), isError: true}
+		}
+		return statusMsg{text: fmt.Sprintf("exported %d repos to %s", len(repos), path)}
+	}
+}
+
+// writeExport writes into the working directory, picking a name that does
+// not clobber an earlier export.
+func writeExport(repos []data.Repo, opts export.Options) (string, error) {
+	base := "gh-stars-" + time.Now().Format("2006-01-02")
+	ext := export.Extension(opts.Format)
+
+	var file *os.File
+	var path string
+	for i := 0; file == nil; i++ {
+		path = base + ext
+		if i > 0 {
+			path = fmt.Sprintf("%s-%d%s", base, i, ext)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		file = f
+	}
+
+	w := bufio.NewWriter(file)
+	err := export.Write(w, repos, opts)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path, nil
+}
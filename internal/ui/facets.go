@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+const (
+	facetLanguage = "language"
+	facetTopic    = "topic"
+	facetOwner    = "owner"
+	facetFork     = "fork"
+)
+
+var facetGroups = []string{facetLanguage, facetTopic, facetOwner, facetFork}
+
+var facetTitles = map[string]string{
+	facetLanguage: "Language",
+	facetTopic:    "Topic",
+	facetOwner:    "Owner",
+	facetFork:     "Fork",
+}
+
+type facetRow struct {
+	group  string
+	value  string
+	count  int
+	header bool
+}
+
+func repoFacetValues(repo data.Repo, group string) []string {
+	switch group {
+	case facetLanguage:
+		if repo.PrimaryLanguage == "" {
+			return []string{"none"}
+		}
+		return []string{repo.PrimaryLanguage}
+	case facetTopic:
+		return repo.Topics
+	case facetOwner:
+		owner, _, _ := strings.Cut(repo.NameWithOwner, "/")
+		return []string{owner}
+	case facetFork:
+		if repo.IsFork {
+			return []string{"fork"}
+		}
+		return []string{"source"}
+	}
+	return nil
+}
+
+func (m *Model) hasFacetFilter() bool {
+	for _, values := range m.facetFilter {
+		if len(values) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// facetMatch reports whether repo has one of the active values of every
+// group but skip. Values within a group are alternatives.
+func (m *Model) facetMatch(repo data.Repo, skip string) bool {
+	for group, active := range m.facetFilter {
+		if group == skip || len(active) == 0 {
+			continue
+		}
+		found := false
+		for _, value := range repoFacetValues(repo, group) {
+			if active[value] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// applyFacets narrows base (the search and list matches) by the active
+// facets. Each group's counts ignore that group's own filter, so sibling
+// values stay visible and can be added.
+func (m *Model) applyFacets(base []int) []int {
+	filtered := base
+	if m.hasFacetFilter() {
+		filtered = make([]int, 0, len(base))
+		for _, idx := range base {
+			if m.facetMatch(m.repos[idx], "") {
+				filtered = append(filtered, idx)
+			}
+		}
+	}
+	if m.facetsVisible {
+		m.buildFacetRows(base)
+	}
+	return filtered
+}
+
+func (m *Model) buildFacetRows(base []int) {
+	rows := []facetRow{}
+	for _, group := range facetGroups {
+		counts := map[string]int{}
+		for _, idx := range base {
+			repo := m.repos[idx]
+			if !m.facetMatch(repo, group) {
+				continue
+			}
+			for _, value := range repoFacetValues(repo, group) {
+				counts[value]++
+			}
+		}
+		for value := range m.facetFilter[group] {
+			if _, ok := counts[value]; !ok {
+				counts[value] = 0
+			}
+		}
+
+		values := make([]string, 0, len(counts))
+		for value := range counts {
+			values = append(values, value)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if counts[values[i]] != counts[values[j]] {
+				return counts[values[i]] > counts[values[j]]
+			}
+			return strings.ToLower(values[i]) < strings.ToLower(values[j])
+		})
+
+		rows = append(rows, facetRow{group: group, header: true})
+		shown := 0
+		for _, value := range values {
+			// Active values are always listed, even past the limit.
+			if shown >= facetLimit && !m.facetFilter[group][value] {
+				continue
+			}
+			rows = append(rows, facetRow{group: group, value: value, count: counts[value]})
+			shown++
+		}
+	}
+	m.facetRows = rows
+	m.moveFacetCursor(0)
+}
+
+func (m *Model) toggleFacets() {
+	m.facetsVisible = !m.facetsVisible
+	m.facetsFocused = m.facetsVisible
+	m.setSize(m.width, m.height)
+	m.resizeEditor()
+	m.applyFilter()
+}
+
+func (m *Model) toggleFacet() {
+	if m.facetCursor < 0 || m.facetCursor >= len(m.facetRows) {
+		return
+	}
+	row := m.facetRows[m.facetCursor]
+	if row.header {
+		return
+	}
+	if m.facetFilter[row.group] == nil {
+		m.facetFilter[row.group] = map[string]bool{}
+	}
+	if m.facetFilter[row.group][row.value] {
+		delete(m.facetFilter[row.group], row.value)
+	} else {
+		m.facetFilter[row.group][row.value] = true
+	}
+	m.cursor = 0
+	m.offset = 0
+	m.applyFilter()
+}
+
+func (m *Model) clearFacets() {
+	m.facetFilter = map[string]map[string]bool{}
+	m.applyFilter()
+}
+
+// moveFacetCursor moves by delta rows, skipping group headers.
+func (m *Model) moveFacetCursor(delta int) {
+	if len(m.facetRows) == 0 {
+		m.facetCursor = 0
+		return
+	}
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	cursor := clamp(m.facetCursor+delta, 0, len(m.facetRows)-1)
+	for cursor >= 0 && cursor < len(m.facetRows) && m.facetRows[cursor].header {
+		cursor += step
+	}
+	if cursor < 0 || cursor >= len(m.facetRows) {
+		// Ran past the edge; search back the other way.
+		cursor = clamp(m.facetCursor, 0, len(m.facetRows)-1)
+		for cursor < len(m.facetRows)-1 && m.facetRows[cursor].header {
+			cursor++
+		}
+	}
+	m.facetCursor = cursor
+}
+
+func (m Model) facetLabel() string {
+	parts := []string{}
+	for _, group := range facetGroups {
+		values := make([]string, 0, len(m.facetFilter[group]))
+		for value := range m.facetFilter[group] {
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		sort.Strings(values)
+		parts = append(parts, group+": "+strings.Join(values, ","))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m Model) renderFacets(height, width int) string {
+	lines := []string{}
+	cursorLine := 0
+	for i, row := range m.facetRows {
+		if row.header {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, padRight(m.styles.PanelTitle.Render(truncate(facetTitles[row.group], width)), width))
+			continue
+		}
+
+		marker := "○ "
+		if m.facetFilter[row.group][row.value] {
+			marker = "● "
+		}
+		line := renderLineWithWidth(truncate(marker+row.value, max(1, width-6)), fmt.Sprintf("%d", row.count), width)
+		switch {
+		case m.facetsFocused && i == m.facetCursor:
+			line = m.styles.ListRowSelected.Render(line)
+			cursorLine = len(lines)
+		case row.count == 0:
+			line = m.styles.Muted.Render(line)
+		default:
+			line = m.styles.ListRow.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	offset := max(0, cursorLine-height+1)
+	lines = lines[min(offset, len(lines)):]
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return strings.Join(lines, "\n")
+}
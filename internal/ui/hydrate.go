@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gh "github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type hydrateTickMsg struct{}
+
+type hydratedMsg struct {
+	fresh map[string]data.Repo
+	err   error
+}
+
+// scheduleHydrate arms the metadata refresh timer for when the current
+// metadata goes stale. It runs independently of the star sync interval.
+func (m Model) scheduleHydrate() tea.Cmd {
+	if m.hydrateInterval <= 0 || m.client == nil {
+		return nil
+	}
+	wait := time.Duration(0)
+	if !m.hydratedAt.IsZero() {
+		wait = time.Until(m.hydratedAt.Add(m.hydrateInterval))
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return hydrateTickMsg{}
+	})
+}
+
+func (m Model) startHydrate() (Model, tea.Cmd) {
+	if m.hydrating {
+		return m, nil
+	}
+	retry := tea.Tick(hydrateRetry, func(time.Time) tea.Msg {
+		return hydrateTickMsg{}
+	})
+	if m.loading {
+		// Let the running sync finish first; it may add repos worth refreshing.
+		return m, retry
+	}
+	if !m.hydratedAt.IsZero() && time.Since(m.hydratedAt) < m.hydrateInterval {
+		// A full sync refreshed everything since this tick was armed.
+		return m, m.scheduleHydrate()
+	}
+	if len(m.repos) == 0 {
+		m.hydratedAt = time.Now().UTC()
+		return m, m.scheduleHydrate()
+	}
+	if data.MissingIDs(m.repos) {
+		// Older caches have no IDs to look repos up by. One full sync
+		// matches them by name and brings their metadata up to date too.
+		m, cmd := m.startReconcile()
+		m.status = "full sync to update cached repos"
+		return m, tea.Batch(cmd, retry)
+	}
+
+	ids := data.RepoIDs(m.repos)
+	m.hydrating = true
+	return m, hydrateCmd(m.ctx, m.client, ids)
+}
+
+func (m Model) handleHydrated(msg hydratedMsg) (Model, tea.Cmd) {
+	m.hydrating = false
+	if msg.err != nil {
+		m.status = fmt.Sprintf("metadata refresh failed: %v", msg.err)
+		m.statusIsError = true
+		return m, tea.Tick(hydrateRetry, func(time.Time) tea.Msg {
+			return hydrateTickMsg{}
+		})
+	}
+
+	repos, changed := data.ApplyMetadata(m.repos, msg.fresh)
+	m.hydratedAt = time.Now().UTC()
+	m.replaceRepos(repos)
+	m.persistCache()
+	if !m.statusIsError {
+		m.status = fmt.Sprintf("metadata refreshed (%d updated)", changed)
+	}
+	return m, m.scheduleHydrate()
+}
+
+func hydrateCmd(ctx context.Context, client *gh.GraphQLClient, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		fresh, err := data.FetchReposByID(ctx, client, ids)
+		return hydratedMsg{fresh: fresh, err: err}
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Actions that can be rebound. Keys inside modals (pickers, editors and
+// prompts) and ctrl+c are fixed.
+const (
+	actionQuit            = "quit"
+	actionSearch          = "search"
+	actionUp              = "up"
+	actionDown            = "down"
+	actionPageUp          = "page_up"
+	actionPageDown        = "page_down"
+	actionTop             = "top"
+	actionBottom          = "bottom"
+	actionOpen            = "open"
+	actionCopy            = "copy"
+	actionReconcile       = "reconcile"
+	actionRefresh         = "refresh"
+	actionCancelSync      = "cancel_sync"
+	actionSort            = "sort"
+	actionUnstar          = "unstar"
+	actionUndo            = "undo"
+	actionNote            = "note"
+	actionTags            = "tags"
+	actionLists           = "lists"
+	actionListMembership  = "list_membership"
+	actionExport          = "export"
+	actionClone           = "clone"
+	actionPreviewDown     = "preview_down"
+	actionPreviewUp       = "preview_up"
+	actionPreviewHalfDown = "preview_half_down"
+	actionPreviewHalfUp   = "preview_half_up"
+	actionSelect          = "select"
+	actionSelectRange     = "select_range"
+	actionSelectAll       = "select_all"
+	actionClearSelection  = "clear_selection"
+	actionFacets          = "facets"
+	actionFocusFacets     = "focus_facets"
+	actionClearFacets     = "clear_facets"
+	actionStats           = "stats"
+	actionCleanup         = "cleanup"
+	actionUserStars       = "user_stars"
+	actionNextTab         = "next_tab"
+	actionPrevTab         = "prev_tab"
+	actionCloseTab        = "close_tab"
+	actionStarToo         = "star_too"
+)
+
+var defaultKeys = map[string][]string{
+	actionQuit:            {"q"},
+	actionSearch:          {"/"},
+	actionUp:              {"k", "up"},
+	actionDown:            {"j", "down"},
+	actionPageUp:          {"pgup"},
+	actionPageDown:        {"pgdown"},
+	actionTop:             {"g"},
+	actionBottom:          {"G"},
+	actionOpen:            {"enter"},
+	actionCopy:            {"y"},
+	actionReconcile:       {"R"},
+	actionRefresh:         {"r"},
+	actionCancelSync:      {"ctrl+x"},
+	actionSort:            {"s"},
+	actionUnstar:          {"u"},
+	actionUndo:            {"z"},
+	actionNote:            {"n"},
+	actionTags:            {"t"},
+	actionLists:           {"l"},
+	actionListMembership:  {"a"},
+	actionExport:          {"e"},
+	actionClone:           {"c"},
+	actionPreviewDown:     {"J"},
+	actionPreviewUp:       {"K"},
+	actionPreviewHalfDown: {"ctrl+d"},
+	actionPreviewHalfUp:   {"ctrl+u"},
+	actionSelect:          {" "},
+	actionSelectRange:     {"V"},
+	actionSelectAll:       {"*"},
+	actionClearSelection:  {"esc"},
+	actionFacets:          {"f"},
+	actionFocusFacets:     {"tab"},
+	actionClearFacets:     {"F"},
+	actionStats:           {"S"},
+	actionCleanup:         {"X"},
+	actionUserStars:       {"O"},
+	actionNextTab:         {"]"},
+	actionPrevTab:         {"["},
+	actionCloseTab:        {"x"},
+	actionStarToo:         {"+"},
+}
+
+// KeyMap resolves pressed keys to actions.
+type KeyMap struct {
+	actions map[string]string
+	keys    map[string][]string
+}
+
+// Actions lists the names accepted by NewKeyMap.
+func Actions() []string {
+	names := make([]string, 0, len(defaultKeys))
+	for action := range defaultKeys {
+		names = append(names, action)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func DefaultKeyMap() KeyMap {
+	keys, _ := NewKeyMap(nil)
+	return keys
+}
+
+// NewKeyMap starts from the default bindings and replaces those of every
+// action in overrides. A key may only be bound to one action.
+func NewKeyMap(overrides map[string][]string) (KeyMap, error) {
+	keys := make(map[string][]string, len(defaultKeys))
+	for action, bound := range defaultKeys {
+		keys[action] = bound
+	}
+	for action, bound := range overrides {
+		if _, ok := defaultKeys[action]; !ok {
+			return KeyMap{}, fmt.Errorf("unknown action %q (want one of %s)", action, strings.Join(Actions(), ", "))
+		}
+		if len(bound) == 0 {
+			return KeyMap{}, fmt.Errorf("action %q has no keys", action)
+		}
+		keys[action] = bound
+	}
+
+	actions := make(map[string]string)
+	for _, action := range Actions() {
+		for _, key := range keys[action] {
+			if key == "ctrl+c" {
+				return KeyMap{}, fmt.Errorf("ctrl+c cannot be rebound (used by %q)", action)
+			}
+			if other, ok := actions[key]; ok {
+				return KeyMap{}, fmt.Errorf("key %q is bound to both %q and %q", key, other, action)
+			}
+			actions[key] = action
+		}
+	}
+	return KeyMap{actions: actions, keys: keys}, nil
+}
+
+func (k KeyMap) Action(key string) string {
+	return k.actions[key]
+}
+
+// Help returns the first key bound to action, as shown in the footer.
+func (k KeyMap) Help(action string) string {
+	bound := k.keys[action]
+	if len(bound) == 0 {
+		return ""
+	}
+	switch bound[0] {
+	case "enter":
+		return "↵"
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	}
+	return bound[0]
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type listsMsg struct {
+	lists []data.StarList
+	err   error
+}
+
+type listsUpdatedMsg struct {
+	repo     data.Repo
+	previous []data.StarList
+	err      error
+}
+
+func (m *Model) activeList() *data.StarList {
+	if m.activeListID == "" {
+		return nil
+	}
+	for i := range m.lists {
+		if m.lists[i].ID == m.activeListID {
+			return &m.lists[i]
+		}
+	}
+	return nil
+}
+
+// inActiveList reports whether repo belongs to the list the view is scoped
+// to. With no list selected every repo is in scope.
+func (m *Model) inActiveList(repo data.Repo) bool {
+	if m.activeListMembers == nil {
+		return true
+	}
+	_, ok := m.activeListMembers[repo.NameWithOwner]
+	return ok
+}
+
+func (m *Model) setActiveList(id string) {
+	m.activeListID = id
+	m.rebuildListMembers()
+	m.cursor = 0
+	m.offset = 0
+	m.applyFilter()
+}
+
+func (m *Model) setLists(lists []data.StarList) {
+	m.lists = lists
+	m.rebuildListMembers()
+	m.applyFilter()
+}
+
+func (m *Model) rebuildListMembers() {
+	m.activeListMembers = nil
+	list := m.activeList()
+	if list == nil {
+		m.activeListID = ""
+		return
+	}
+	m.activeListMembers = make(map[string]struct{}, len(list.Repos))
+	for _, name := range list.Repos {
+		m.activeListMembers[name] = struct{}{}
+	}
+}
+
+func (m *Model) openListSwitcher() {
+	items := []pickerItem{{label: "All stars", detail: fmt.Sprintf("%d", len(m.repos)), value: "", checked: m.activeListID == ""}}
+	cursor := 0
+	for i, list := range m.lists {
+		label := list.Name
+		if list.Private {
+			label += " 🔒"
+		}
+		items = append(items, pickerItem{label: label, detail: fmt.Sprintf("%d", len(list.Repos)), value: list.ID})
+		if list.ID == m.activeListID {
+			cursor = i + 1
+		}
+	}
+
+	title := "Star lists"
+	if len(m.lists) == 0 {
+		title = "Star lists (none yet, syncing...)"
+	}
+	m.picker = &picker{
+		title:  title,
+		items:  items,
+		cursor: cursor,
+		onSubmit: func(m Model, p picker) (Model, tea.Cmd) {
+			m.setActiveList(p.selected().value)
+			if list := m.activeList(); list != nil {
+				m.status = "list: " + list.Name
+			} else {
+				m.status = "all stars"
+			}
+			m.statusIsError = false
+			return m, nil
+		},
+	}
+}
+
+func (m *Model) openListMembership(repo data.Repo) {
+	if len(m.lists) == 0 {
+		m.status = "no star lists"
+		m.statusIsError = false
+		return
+	}
+
+	items := make([]pickerItem, 0, len(m.lists))
+	for _, list := range m.lists {
+		items = append(items, pickerItem{
+			label:   list.Name,
+			detail:  fmt.Sprintf("%d", len(list.Repos)),
+			value:   list.ID,
+			checked: list.Contains(repo.NameWithOwner),
+		})
+	}
+
+	m.picker = &picker{
+		title: "Lists for " + repo.NameWithOwner,
+		items: items,
+		multi: true,
+		onSubmit: func(m Model, p picker) (Model, tea.Cmd) {
+			previous := m.lists
+			listIDs := p.checkedValues()
+			m.setLists(data.WithRepoLists(m.lists, repo.NameWithOwner, listIDs))
+			m.status = fmt.Sprintf("updating lists for %s", repo.NameWithOwner)
+			m.statusIsError = false
+			return m, m.setRepoListsCmd(repo, listIDs, previous)
+		},
+	}
+}
+
+func (m Model) handleListsUpdated(msg listsUpdatedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		// Only roll back this repo; other updates may have succeeded since.
+		name := msg.repo.NameWithOwner
+		m.setLists(data.WithRepoLists(m.lists, name, repoListIDs(msg.previous, name)))
+		m.persistCache()
+		m.status = fmt.Sprintf("list update for %s failed: %v", name, msg.err)
+		m.statusIsError = true
+		return m, nil
+	}
+	m.status = "lists updated"
+	m.statusIsError = false
+	m.persistCache()
+	return m, nil
+}
+
+func (m Model) repoListNames(repo data.Repo) []string {
+	names := []string{}
+	for _, list := range m.lists {
+		if list.Contains(repo.NameWithOwner) {
+			names = append(names, list.Name)
+		}
+	}
+	return names
+}
+
+func (m Model) listLabel() string {
+	list := m.activeList()
+	if list == nil {
+		return ""
+	}
+	return "list: " + strings.TrimSpace(list.Name)
+}
+
+// fetchListsCmd loads the viewer's star lists. Other users' stars have no
+// lists the viewer could manage.
+func (m Model) fetchListsCmd() tea.Cmd {
+	ctx, client := m.ctx, m.client
+	if client == nil || m.readOnly {
+		return nil
+	}
+	return func() tea.Msg {
+		lists, err := data.FetchStarLists(ctx, client)
+		return listsMsg{lists: lists, err: err}
+	}
+}
+
+func (m Model) setRepoListsCmd(repo data.Repo, listIDs []string, previous []data.StarList) tea.Cmd {
+	client := m.client
+	return m.mutationCmd(func(ctx context.Context) tea.Msg {
+		err := data.SetRepoLists(ctx, client, repo, listIDs)
+		return listsUpdatedMsg{repo: repo, previous: previous, err: err}
+	})
+}
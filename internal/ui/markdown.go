@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	htmlTagPattern     = regexp.MustCompile(`<[^>]+>`)
+	orderedItemPattern = regexp.MustCompile(`^(\d+)[.)]\s+`)
+	imagePattern       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+)
+
+type inlineKind int
+
+const (
+	inlinePlain inlineKind = iota
+	inlineCode
+	inlineBold
+	inlineItalic
+	inlineLink
+)
+
+type inlineSpan struct {
+	text string
+	kind inlineKind
+}
+
+// renderMarkdown renders a README into styled lines of at most width cells.
+// It covers the subset of markdown that shows up in most READMEs: headings,
+// paragraphs, lists, quotes, fenced code, rules and inline emphasis/links.
+// Raw HTML is stripped.
+func renderMarkdown(src string, width int, styles Styles) []string {
+	if width <= 0 {
+		return nil
+	}
+
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+
+	lines := []string{}
+	paragraph := []string{}
+	blank := func() {
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+	}
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		text := strings.Join(paragraph, " ")
+		paragraph = paragraph[:0]
+		lines = append(lines, wrapSpans(parseInline(text), width, "", "", lipgloss.NewStyle(), styles)...)
+		blank()
+	}
+
+	inFence := false
+	fence := ""
+	for _, raw := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(raw)
+
+		if inFence {
+			if strings.HasPrefix(trimmed, fence) {
+				inFence = false
+				blank()
+				continue
+			}
+			lines = append(lines, styles.MarkdownCode.Render(truncate("  "+raw, width)))
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			inFence = true
+			fence = trimmed[:3]
+			continue
+		}
+
+		trimmed = strings.TrimSpace(htmlTagPattern.ReplaceAllString(trimmed, ""))
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			blank()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			title := strings.TrimSpace(strings.Trim(trimmed, "#"))
+			style := styles.MarkdownHeading
+			if level > 1 {
+				style = style.UnsetUnderline()
+			}
+			for _, line := range wrapLines([]string{stripInline(title)}, width) {
+				lines = append(lines, style.Render(line))
+			}
+			blank()
+		case isRule(trimmed):
+			flush()
+			lines = append(lines, styles.Divider.Render(strings.Repeat("─", width)))
+			blank()
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			text := strings.TrimSpace(strings.TrimLeft(trimmed, ">"))
+			lines = append(lines, wrapSpans(parseInline(text), width, "│ ", "│ ", styles.MarkdownQuote, styles)...)
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ "):
+			flush()
+			indent := strings.Repeat("  ", min(3, (len(raw)-len(strings.TrimLeft(raw, " ")))/2))
+			text := strings.TrimSpace(trimmed[2:])
+			lines = append(lines, wrapSpans(parseInline(text), width, indent+"• ", indent+"  ", lipgloss.NewStyle(), styles)...)
+		case orderedItemPattern.MatchString(trimmed):
+			flush()
+			marker := orderedItemPattern.FindStringSubmatch(trimmed)[1] + ". "
+			text := orderedItemPattern.ReplaceAllString(trimmed, "")
+			lines = append(lines, wrapSpans(parseInline(text), width, marker, strings.Repeat(" ", len(marker)), lipgloss.NewStyle(), styles)...)
+		case strings.HasPrefix(trimmed, "|"):
+			flush()
+			if strings.Trim(trimmed, "|-: ") == "" {
+				continue
+			}
+			lines = append(lines, styles.Muted.Render(truncate(stripInline(trimmed), width)))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func isRule(line string) bool {
+	if len(line) < 3 {
+		return false
+	}
+	for _, ch := range []string{"-", "*", "_"} {
+		if strings.Trim(strings.ReplaceAll(line, " ", ""), ch) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInline splits text into spans of code, emphasis and links. Unmatched
+// markers are kept as literal text.
+func parseInline(text string) []inlineSpan {
+	text = imagePattern.ReplaceAllString(text, "")
+	spans := []inlineSpan{}
+	var plain strings.Builder
+	emit := func(s string, kind inlineKind) {
+		if plain.Len() > 0 {
+			spans = append(spans, inlineSpan{text: plain.String(), kind: inlinePlain})
+			plain.Reset()
+		}
+		if s != "" {
+			spans = append(spans, inlineSpan{text: s, kind: kind})
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				emit(rest[1:1+end], inlineCode)
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				emit(rest[2:2+end], inlineBold)
+				i += end + 4
+				continue
+			}
+		case rest[0] == '*':
+			if end := strings.IndexByte(rest[1:], '*'); end > 0 && rest[1] != ' ' {
+				emit(rest[1:1+end], inlineItalic)
+				i += end + 2
+				continue
+			}
+		case rest[0] == '[':
+			closeText := strings.Index(rest, "](")
+			if closeText > 0 {
+				if closeURL := strings.IndexByte(rest[closeText:], ')'); closeURL > 0 {
+					emit(rest[1:closeText], inlineLink)
+					i += closeText + closeURL + 1
+					continue
+				}
+			}
+		}
+		plain.WriteByte(text[i])
+		i++
+	}
+	emit("", inlinePlain)
+
+	return spans
+}
+
+func stripInline(text string) string {
+	var b strings.Builder
+	for _, span := range parseInline(text) {
+		b.WriteString(span.text)
+	}
+	return b.String()
+}
+
+type styledWord struct {
+	parts []inlineSpan
+	width int
+}
+
+// wrapSpans word-wraps inline spans to width, prefixing the first line with
+// first and continuation lines with rest.
+func wrapSpans(spans []inlineSpan, width int, first, rest string, base lipgloss.Style, styles Styles) []string {
+	words := []styledWord{}
+	current := styledWord{}
+	for _, span := range spans {
+		for i, piece := range strings.Split(span.text, " ") {
+			if i > 0 && len(current.parts) > 0 {
+				words = append(words, current)
+				current = styledWord{}
+			}
+			if piece == "" {
+				continue
+			}
+			current.parts = append(current.parts, inlineSpan{text: piece, kind: span.kind})
+			current.width += lipgloss.Width(piece)
+		}
+	}
+	if len(current.parts) > 0 {
+		words = append(words, current)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	render := func(word styledWord) string {
+		var b strings.Builder
+		for _, part := range word.parts {
+			style := base
+			switch part.kind {
+			case inlineCode:
+				style = styles.MarkdownCode
+			case inlineBold:
+				style = base.Bold(true)
+			case inlineItalic:
+				style = base.Italic(true)
+			case inlineLink:
+				style = styles.MarkdownLink
+			}
+			b.WriteString(style.Render(part.text))
+		}
+		return b.String()
+	}
+
+	lines := []string{}
+	prefix := first
+	var line strings.Builder
+	lineWidth := 0
+	for _, word := range words {
+		available := max(1, width-lipgloss.Width(prefix))
+		if lineWidth > 0 && lineWidth+1+word.width > available {
+			lines = append(lines, base.Render(prefix)+line.String())
+			line.Reset()
+			lineWidth = 0
+			prefix = rest
+			available = max(1, width-lipgloss.Width(prefix))
+		}
+		if lineWidth > 0 {
+			line.WriteString(" ")
+			lineWidth++
+		}
+		if word.width > available {
+			word = styledWord{parts: []inlineSpan{{text: truncate(stripWord(word), available), kind: word.parts[0].kind}}, width: available}
+		}
+		line.WriteString(render(word))
+		lineWidth += word.width
+	}
+	if lineWidth > 0 {
+		lines = append(lines, base.Render(prefix)+line.String())
+	}
+
+	return lines
+}
+
+func stripWord(word styledWord) string {
+	var b strings.Builder
+	for _, part := range word.parts {
+		b.WriteString(part.text)
+	}
+	return b.String()
+}
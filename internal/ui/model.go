@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/atotto/clipboard"
@@ -17,10 +17,13 @@ import (
 	"github.com/cli/go-gh/v2/pkg/browser"
 
 	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/search"
 )
 
 type Model struct {
-	client *gh.GraphQLClient
+	source   data.StarSource
+	client   *gh.GraphQLClient
+	readOnly bool
 
 	styles      Styles
 	spinner     spinner.Model
@@ -34,6 +37,7 @@ type Model struct {
 
 	repos      []data.Repo
 	filtered   []int
+	matches    map[int]search.Result
 	cursor     int
 	offset     int
 	totalCount int
@@ -44,6 +48,8 @@ type Model struct {
 
 	searchFocused bool
 	sortMode      string
+	query         search.Query
+	queryErr      error
 
 	status        string
 	statusIsError bool
@@ -51,13 +57,102 @@ type Model struct {
 	cachePath  string
 	cacheIndex map[string]struct{}
 	cacheDirty bool
+	syncer     *Syncer
 
 	deferRefresh bool
 	pendingNew   []data.Repo
+
+	reconciling bool
+	reconciled  []data.Repo
+
+	// ctx is cancelled when the program exits; syncCtx, a child of it, when
+	// the current tab's sync is cancelled.
+	ctx        context.Context
+	jobs       *sync.WaitGroup
+	mutations  *pendingMutations
+	syncCtx    context.Context
+	cancelSync context.CancelFunc
+	syncBase   []data.Repo
+
+	hydrating       bool
+	hydratedAt      time.Time
+	hydrateInterval time.Duration
+	inactiveAfter   time.Duration
+
+	readmeDir      string
+	readmes        map[string]readmeState
+	readmeRendered map[string][]string
+	readmePending  string
+	detailsDir     string
+	details        map[string]detailsState
+	previewRepo    string
+	previewOffset  int
+
+	lists             []data.StarList
+	activeListID      string
+	activeListMembers map[string]struct{}
+
+	annotations     data.Annotations
+	annotationsPath string
+	editor          *annotationEditor
+
+	picker       *picker
+	prompt       *confirmPrompt
+	undo         *undoState
+	unstarring   map[string]bool
+	restarQueued map[string]bool
+
+	pickMode bool
+	picked   []data.Repo
+
+	cloneLayout   string
+	cloneProtocol string
+	cloned        map[string]bool
+	cloning       map[string]bool
+
+	keys   KeyMap
+	layout Layout
+
+	selection   map[string]struct{}
+	rangeAnchor string
+
+	facetsVisible bool
+	facetsFocused bool
+	facetWidth    int
+	facetRows     []facetRow
+	facetCursor   int
+	facetFilter   map[string]map[string]bool
+
+	showStats   bool
+	statsOffset int
+
+	tabs      []starTab
+	activeTab int
+
+	rateLimit data.RateLimit
+}
+
+type confirmPrompt struct {
+	text      string
+	onConfirm func(m Model) (Model, tea.Cmd)
+}
+
+type readmeState struct {
+	readme  data.Readme
+	loading bool
+	err     error
+}
+
+type detailsState struct {
+	details data.Details
+	loading bool
+	err     error
 }
 
 type starsPageMsg struct {
-	page data.StarsPage
+	source data.StarSource
+	ctx    context.Context
+	page   data.StarsPage
 }
 
 type statusMsg struct {
@@ -66,11 +161,103 @@ type statusMsg struct {
 }
 
 type errorMsg struct {
-	err error
+	source  data.StarSource
+	ctx     context.Context
+	err     error
+	attempt int
+	retry   tea.Cmd
+}
+
+type readmeTickMsg struct {
+	name string
+}
+
+type readmeMsg struct {
+	name   string
+	readme data.Readme
+	err    error
+}
+
+type detailsMsg struct {
+	name    string
+	details data.Details
+	err     error
+}
+
+type Options struct {
+	Context         context.Context
+	PageSize        int
+	CachePath       string
+	FetchOnStart    bool
+	BackgroundSync  bool
+	Syncer          *Syncer
+	HydrateInterval time.Duration
+	InactiveAfter   time.Duration
+	Annotations     data.Annotations
+	AnnotationsPath string
+	Query           string
+	PickMode        bool
+	CloneLayout     string
+	CloneProtocol   string
+	SortMode        string
+	Keys            *KeyMap
+	Theme           map[string]StyleOverride
+	Layout          *Layout
 }
 
-func NewModel(client *gh.GraphQLClient, pageSize int, cachePath string, cachedRepos []data.Repo, fetchOnStart bool, backgroundSync bool) Model {
+// Layout controls when the preview panel is shown and how wide the list is.
+type Layout struct {
+	PreviewMinWidth int
+	ListRatio       float64
+	ListMinWidth    int
+}
+
+func DefaultLayout() Layout {
+	return Layout{PreviewMinWidth: 120, ListRatio: 0.55, ListMinWidth: 30}
+}
+
+func (l Layout) Validate() error {
+	if l.PreviewMinWidth < 0 {
+		return fmt.Errorf("preview_min_width must not be negative")
+	}
+	if l.ListRatio <= 0 || l.ListRatio >= 1 {
+		return fmt.Errorf("list_ratio must be between 0 and 1")
+	}
+	if l.ListMinWidth < 1 {
+		return fmt.Errorf("list_min_width must be positive")
+	}
+	return nil
+}
+
+// NewModel builds the UI for the stars of source. A nil source works off the
+// cache alone.
+func NewModel(source data.StarSource, cache data.Cache, opts Options) Model {
+	var client *gh.GraphQLClient
+	if source != nil {
+		client = source.Client()
+	}
 	styles := DefaultStyles()
+	if themed, err := styles.WithTheme(opts.Theme); err == nil {
+		styles = themed
+	}
+	keys := DefaultKeyMap()
+	if opts.Keys != nil {
+		keys = *opts.Keys
+	}
+	layout := DefaultLayout()
+	if opts.Layout != nil {
+		layout = *opts.Layout
+	}
+	sortMode := opts.SortMode
+	if sortMode == "" {
+		sortMode = "default"
+	}
+	inactiveAfter := opts.InactiveAfter
+	if inactiveAfter <= 0 {
+		inactiveAfter = data.DefaultInactiveAfter
+	}
+	cachedRepos := cache.Repos
+	fetchOnStart := opts.FetchOnStart
 
 	sp := spinner.New(spinner.WithSpinner(spinner.Spinner{
 		Frames: []string{"-", "\\", "|", "/"},
@@ -80,7 +267,7 @@ func NewModel(client *gh.GraphQLClient, pageSize int, cachePath string, cachedRe
 
 	ti := textinput.New()
 	ti.Prompt = "Search: "
-	ti.Placeholder = "name, description, repo/name"
+	ti.Placeholder = "name, lang:go, topic:cli, stars:>1000, -fork:true"
 	ti.CharLimit = 200
 	ti.Blur()
 	ti.PromptStyle = styles.SearchPrompt
@@ -98,7 +285,7 @@ func NewModel(client *gh.GraphQLClient, pageSize int, cachePath string, cachedRe
 		if fetchOnStart {
 			status = "refreshing"
 			deferRefresh = true
-		} else if backgroundSync {
+		} else if opts.BackgroundSync {
 			status = "syncing (bg)"
 		} else {
 			status = "cached"
@@ -106,35 +293,101 @@ func NewModel(client *gh.GraphQLClient, pageSize int, cachePath string, cachedRe
 	}
 
 	model := Model{
+		source:        source,
 		client:        client,
+		readOnly:      data.IsReadOnly(source),
 		styles:        styles,
 		spinner:       sp,
 		searchInput:   ti,
-		pageSize:      pageSize,
+		pageSize:      opts.PageSize,
 		loading:       fetchOnStart,
 		status:        status,
 		statusIsError: false,
-		cachePath:     cachePath,
+		cachePath:     opts.CachePath,
+		syncer:        opts.Syncer,
 		cacheIndex:    cacheIndex,
 		repos:         cachedRepos,
 		deferRefresh:  deferRefresh,
-		sortMode:      "default",
+		sortMode:      sortMode,
+
+		lists:           cache.Lists,
+		annotations:     opts.Annotations,
+		annotationsPath: opts.AnnotationsPath,
+		hydratedAt:      cache.HydratedAt,
+		hydrateInterval: opts.HydrateInterval,
+		inactiveAfter:   inactiveAfter,
+
+		readmeDir:      data.ReadmeDir(opts.CachePath),
+		readmes:        make(map[string]readmeState),
+		readmeRendered: make(map[string][]string),
+		detailsDir:     data.DetailsDir(opts.CachePath),
+		details:        make(map[string]detailsState),
+
+		unstarring:   make(map[string]bool),
+		restarQueued: make(map[string]bool),
+
+		cloneLayout:   opts.CloneLayout,
+		cloneProtocol: opts.CloneProtocol,
+		cloned:        make(map[string]bool),
+		cloning:       make(map[string]bool),
+
+		keys:   keys,
+		layout: layout,
+
+		selection:   make(map[string]struct{}),
+		tabs:        make([]starTab, 1),
+		facetFilter: map[string]map[string]bool{},
+	}
+	if model.annotations == nil {
+		model.annotations = data.Annotations{}
+	}
+	if opts.Query != "" {
+		model.searchInput.SetValue(opts.Query)
+	}
+	if opts.PickMode {
+		model.pickMode = true
+		model.focusSearch()
+	}
+	model.jobs = &sync.WaitGroup{}
+	model.mutations = &pendingMutations{}
+	model.ctx = opts.Context
+	if model.ctx == nil {
+		model.ctx = context.Background()
+	}
+	if model.loading {
+		model.startSync()
 	}
 	model.applyFilter()
 	return model
 }
 
 func (m Model) Init() tea.Cmd {
+	scan := scanClonesCmd(m.cloneLayout, m.repos)
 	if !m.loading {
-		return nil
+		return tea.Batch(m.fetchListsCmd(), m.scheduleHydrate(), scan)
 	}
-	return tea.Batch(m.spinner.Tick, fetchStarsPageCmd(m.client, m.pageSize, m.nextCursor))
+	return tea.Batch(m.spinner.Tick, fetchStarsPageCmd(m.syncCtx, m.source, m.pageSize, m.nextCursor), m.fetchListsCmd(), m.scheduleHydrate(), scan)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	previewCmd := next.syncPreview()
+	return next, tea.Batch(cmd, previewCmd)
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
+	if i, ok := m.messageTab(msg); ok && i != m.activeTab {
+		if i < 0 {
+			// The tab was closed while its request was in flight.
+			return m, nil
+		}
+		return m.onTab(i, func(m Model) (Model, tea.Cmd) { return m.update(msg) })
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.setSize(msg.Width, msg.Height)
+		m.resizeEditor()
 		m.applyFilter()
 		return m, nil
 	case spinner.TickMsg:
@@ -144,6 +397,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 	case starsPageMsg:
+		if msg.ctx.Err() != nil {
+			return m, nil
+		}
 		foundCached := false
 		newRepos := make([]data.Repo, 0, len(msg.page.Repos))
 		for _, repo := range msg.page.Repos {
@@ -194,16 +450,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if m.cacheDirty && !m.loading {
-			if err := data.SaveCache(m.cachePath, m.repos); err != nil {
-				m.status = fmt.Sprintf("cache save failed: %v", err)
-				m.statusIsError = true
-			} else {
-				m.cacheDirty = false
-			}
+			m.persistCache()
 		}
 
 		if m.loading {
-			return m, fetchStarsPageCmd(m.client, m.pageSize, m.nextCursor)
+			return m, m.throttle(msg.page.RateLimit, fetchStarsPageCmd(m.syncCtx, m.source, m.pageSize, m.nextCursor))
+		}
+		m.stopSync()
+		m.rateLimit = msg.page.RateLimit
+		return m, scanClonesCmd(m.cloneLayout, m.repos)
+	case readmeTickMsg:
+		if m.readmePending == msg.name {
+			m.readmePending = ""
+		}
+		repo := m.selectedRepo()
+		if repo == nil || repo.NameWithOwner != msg.name {
+			return m, nil
+		}
+		cmds := []tea.Cmd{}
+		if _, ok := m.readmes[msg.name]; !ok {
+			m.readmes[msg.name] = readmeState{loading: true}
+			cmds = append(cmds, loadReadmeCmd(m.ctx, m.client, m.readmeDir, msg.name))
+		}
+		if _, ok := m.details[msg.name]; !ok {
+			m.details[msg.name] = detailsState{loading: true}
+			cmds = append(cmds, loadDetailsCmd(m.ctx, m.client, m.detailsDir, msg.name))
+		}
+		return m, tea.Batch(cmds...)
+	case readmeMsg:
+		m.readmes[msg.name] = readmeState{readme: msg.readme, err: msg.err}
+		return m, nil
+	case detailsMsg:
+		m.details[msg.name] = detailsState{details: msg.details, err: msg.err}
+		return m, nil
+	case hydrateTickMsg:
+		return m.startHydrate()
+	case hydratedMsg:
+		return m.handleHydrated(msg)
+	case reconcilePageMsg:
+		return m.handleReconcilePage(msg)
+	case listsMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("star lists unavailable: %v", msg.err)
+			m.statusIsError = true
+			return m, nil
+		}
+		m.setLists(msg.lists)
+		m.persistCache()
+		return m, nil
+	case listsUpdatedMsg:
+		return m.handleListsUpdated(msg)
+	case ownerMsg:
+		return m.handleOwner(msg)
+	case starTooMsg:
+		return m.handleStarToo(msg)
+	case starResultMsg:
+		return m.handleStarResult(msg)
+	case cloneProgressMsg:
+		return m.handleCloneProgress(msg)
+	case cloneDoneMsg:
+		return m.handleCloneDone(msg)
+	case clonesScannedMsg:
+		m.cloned = msg.cloned
+		return m, nil
+	case undoExpiredMsg:
+		if m.undo != nil && m.undo.expires.Equal(msg.expires) {
+			m.undo = nil
 		}
 		return m, nil
 	case statusMsg:
@@ -211,18 +523,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusIsError = msg.isError
 		return m, nil
 	case errorMsg:
-		m.loading = false
-		m.err = msg.err
-		m.status = msg.err.Error()
-		m.statusIsError = true
-		return m, nil
+		return m.handleFetchError(msg)
+	case syncedMsg:
+		return m.handleSynced(msg)
+	case retryMsg:
+		return m, msg.cmd
 	case tea.KeyMsg:
 		key := msg.String()
 		switch key {
-		case "ctrl+c", "q":
+		case "ctrl+c":
 			return m, tea.Quit
 		}
 
+		if m.editor != nil {
+			return m.updateEditor(msg)
+		}
+
+		if m.prompt != nil {
+			prompt := m.prompt
+			m.prompt = nil
+			switch key {
+			case "y", "Y", "enter":
+				return prompt.onConfirm(m)
+			}
+			m.status = "cancelled"
+			m.statusIsError = false
+			return m, nil
+		}
+
+		if m.picker != nil {
+			return m.updatePicker(key)
+		}
+
+		action := m.keys.Action(key)
+		if action == actionQuit && !m.searchFocused {
+			return m, tea.Quit
+		}
+
+		if m.pickMode {
+			switch {
+			case key == "enter" || (action == actionOpen && !m.searchFocused):
+				return m.pick()
+			case key == "esc":
+				if !m.searchFocused && len(m.selection) == 0 {
+					return m, tea.Quit
+				}
+			}
+		}
+
 		if m.searchFocused {
 			switch key {
 			case "esc", "enter":
@@ -232,44 +580,95 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}
 
-		switch key {
-		case "/":
+		if m.showStats {
+			switch action {
+			case actionStats, actionClearSelection:
+				m.toggleStats()
+			case actionUp:
+				m.scrollStats(-1)
+			case actionDown:
+				m.scrollStats(1)
+			case actionPageUp, actionPreviewHalfUp:
+				m.scrollStats(-m.listBodyRows() * listRowHeight)
+			case actionPageDown, actionPreviewHalfDown:
+				m.scrollStats(m.listBodyRows() * listRowHeight)
+			case actionTop:
+				m.statsOffset = 0
+			case actionBottom:
+				m.scrollStats(len(m.repos))
+			}
+			return m, nil
+		}
+
+		if m.facetsFocused {
+			switch action {
+			case actionUp:
+				m.moveFacetCursor(-1)
+				return m, nil
+			case actionDown:
+				m.moveFacetCursor(1)
+				return m, nil
+			case actionTop:
+				m.facetCursor = 0
+				m.moveFacetCursor(0)
+				return m, nil
+			case actionBottom:
+				m.facetCursor = len(m.facetRows) - 1
+				m.moveFacetCursor(0)
+				return m, nil
+			case actionSelect, actionOpen:
+				m.toggleFacet()
+				return m, nil
+			case actionFocusFacets, actionClearSelection:
+				m.facetsFocused = false
+				return m, nil
+			}
+		}
+
+		switch action {
+		case actionSearch:
 			m.focusSearch()
 			return m, nil
-		case "up", "k":
+		case actionUp:
 			m.moveCursor(-1)
 			return m, nil
-		case "down", "j":
+		case actionDown:
 			m.moveCursor(1)
 			return m, nil
-		case "pgup":
+		case actionPageUp:
 			m.moveCursor(-m.listBodyRows())
 			return m, nil
-		case "pgdown":
+		case actionPageDown:
 			m.moveCursor(m.listBodyRows())
 			return m, nil
-		case "g":
+		case actionTop:
 			m.moveToTop()
 			return m, nil
-		case "G":
+		case actionBottom:
 			m.moveToBottom()
 			return m, nil
-		case "enter":
-			repo := m.selectedRepo()
-			if repo == nil {
+		case actionOpen:
+			repos := m.targetRepos()
+			if len(repos) == 0 {
 				return m, nil
 			}
-			return m, openRepoCmd(repo.URL)
-		case "y":
-			repo := m.selectedRepo()
-			if repo == nil {
+			return m.openRepos(repos)
+		case actionCopy:
+			repos := m.targetRepos()
+			if len(repos) == 0 {
 				return m, nil
 			}
-			return m, copyURLCmd(repo.URL)
-		case "r", "R":
+			return m, copyURLCmd(repoURLs(repos))
+		case actionReconcile:
+			if m.rejectReadOnly() {
+				return m, nil
+			}
+			return m.startReconcile()
+		case actionRefresh:
 			if m.loading {
 				return m, nil
 			}
+			ctx := m.startSync()
 			m.repos = nil
 			m.filtered = nil
 			m.cacheIndex = make(map[string]struct{})
@@ -280,14 +679,132 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.loading = true
 			m.status = "refreshing"
 			m.applyFilter()
-			return m, tea.Batch(m.spinner.Tick, fetchStarsPageCmd(m.client, m.pageSize, m.nextCursor))
-		case "s":
+			return m, tea.Batch(m.spinner.Tick, fetchStarsPageCmd(ctx, m.source, m.pageSize, m.nextCursor), m.fetchListsCmd())
+		case actionCancelSync:
+			return m.cancelSyncAction()
+		case actionSort:
 			m.cycleSortMode()
 			m.applyFilter()
 			return m, nil
+		case actionUnstar:
+			if m.rejectReadOnly() {
+				return m, nil
+			}
+			m.confirmUnstar(m.targetRepos())
+			return m, nil
+		case actionUndo:
+			if m.rejectReadOnly() {
+				return m, nil
+			}
+			return m.undoUnstar()
+		case actionNote:
+			repo := m.selectedRepo()
+			if repo == nil {
+				return m, nil
+			}
+			return m, m.openNoteEditor(*repo)
+		case actionTags:
+			return m, m.openTagsEditor(m.targetRepos())
+		case actionLists:
+			if m.rejectReadOnly() {
+				return m, nil
+			}
+			m.openListSwitcher()
+			return m, nil
+		case actionListMembership:
+			if m.rejectReadOnly() {
+				return m, nil
+			}
+			repos := m.targetRepos()
+			switch {
+			case len(repos) == 1:
+				m.openListMembership(repos[0])
+			case len(repos) > 1:
+				m.openBulkListAdd(repos)
+			}
+			return m, nil
+		case actionExport:
+			m.openExport()
+			return m, nil
+		case actionClone:
+			repo := m.selectedRepo()
+			if repo == nil {
+				return m, nil
+			}
+			return m.startClone(*repo)
+		case actionPreviewDown:
+			m.scrollPreview(1)
+			return m, nil
+		case actionPreviewUp:
+			m.scrollPreview(-1)
+			return m, nil
+		case actionPreviewHalfDown:
+			m.scrollPreview(m.previewHeight() / 2)
+			return m, nil
+		case actionPreviewHalfUp:
+			m.scrollPreview(-m.previewHeight() / 2)
+			return m, nil
+		case actionFacets:
+			m.toggleFacets()
+			return m, nil
+		case actionStats:
+			m.toggleStats()
+			return m, nil
+		case actionCleanup:
+			m.startCleanup()
+			return m, nil
+		case actionUserStars:
+			return m, m.openUserPrompt()
+		case actionNextTab:
+			m.switchTab(m.activeTab + 1)
+			return m, nil
+		case actionPrevTab:
+			m.switchTab(m.activeTab - 1)
+			return m, nil
+		case actionCloseTab:
+			if m.activeTab > 0 {
+				m.closeTab(m.activeTab)
+				m.status = "tab closed"
+				m.statusIsError = false
+			}
+			return m, nil
+		case actionStarToo:
+			return m.starToo()
+		case actionFocusFacets:
+			if m.facetsVisible {
+				m.facetsFocused = true
+			}
+			return m, nil
+		case actionClearFacets:
+			if m.hasFacetFilter() {
+				m.clearFacets()
+				m.status = "facets cleared"
+				m.statusIsError = false
+			}
+			return m, nil
+		case actionSelect:
+			m.toggleSelected()
+			return m, nil
+		case actionSelectRange:
+			m.selectRange()
+			return m, nil
+		case actionSelectAll:
+			m.selectAllFiltered()
+			return m, nil
+		case actionClearSelection:
+			if len(m.selection) > 0 || m.rangeAnchor != "" {
+				m.clearSelection()
+				m.status = "selection cleared"
+				m.statusIsError = false
+			}
+			return m, nil
 		}
 	}
 
+	if m.editor != nil {
+		return m.updateEditor(msg)
+	}
+
 	if m.searchFocused {
 		prev := m.searchInput.Value()
 		var cmd tea.Cmd
@@ -309,20 +826,55 @@ func (m *Model) setSize(width, height int) {
 	searchInnerWidth := width - (2 * searchBoxBorder) - (2 * searchBoxPadding)
 	m.searchInput.Width = max(0, searchInnerWidth-promptWidth)
 
-	if width < 120 {
+	m.facetWidth = 0
+	if m.facetsVisible {
+		m.facetWidth = min(facetPanelWidth, width/3)
+	}
+	width -= m.facetWidth
+
+	if width < m.layout.PreviewMinWidth {
 		m.listWidth = width
 		m.previewWidth = 0
 		return
 	}
 
 	panelsTotalWidth := width
-	m.listWidth = int(float64(panelsTotalWidth) * 0.55)
-	if m.listWidth < 30 {
-		m.listWidth = 30
+	m.listWidth = int(float64(panelsTotalWidth) * m.layout.ListRatio)
+	if m.listWidth < m.layout.ListMinWidth {
+		m.listWidth = m.layout.ListMinWidth
 	}
 	m.previewWidth = max(0, panelsTotalWidth-m.listWidth)
 }
 
+func (m *Model) persistCache() {
+	cache := data.Cache{Repos: m.repos, HydratedAt: m.hydratedAt, Lists: m.lists}
+	if m.syncer == nil || m.syncer.source != m.source {
+		if err := data.SaveCache(m.cachePath, cache); err != nil {
+			m.status = fmt.Sprintf("cache save failed: %v", err)
+			m.statusIsError = true
+			return
+		}
+		m.cacheDirty = false
+		return
+	}
+
+	saved, merged, err := m.syncer.Save(cache)
+	if err != nil {
+		m.status = fmt.Sprintf("cache save failed: %v", err)
+		m.statusIsError = true
+		return
+	}
+	m.cacheDirty = false
+	if merged {
+		// Another gh-stars instance saved the cache since we last did.
+		m.lists = saved.Lists
+		m.hydratedAt = saved.HydratedAt
+		m.replaceRepos(saved.Repos)
+		m.status = "merged changes from another gh-stars instance"
+		m.statusIsError = false
+	}
+}
+
 func (m *Model) focusSearch() {
 	m.searchFocused = true
 	m.searchInput.Focus()
@@ -360,14 +912,17 @@ func (m *Model) listBodyRows() int {
 }
 
 func (m *Model) applyFilter() {
-	query := strings.TrimSpace(strings.ToLower(m.searchInput.Value()))
-	m.filtered = m.filtered[:0]
-
-	for i, repo := range m.repos {
-		if matchesQuery(repo, query) {
-			m.filtered = append(m.filtered, i)
-		}
+	query, err := search.Parse(m.searchInput.Value(), m.inactiveAfter)
+	if err != nil {
+		// Keep filtering with the last valid query while the user fixes it.
+		m.queryErr = err
+	} else {
+		m.queryErr = nil
+		m.query = query
 	}
+	base, matches := search.Filter(m.repos, m.query, m.annotations, m.inActiveList)
+	m.filtered = m.applyFacets(base)
+	m.matches = matches
 
 	if len(m.filtered) == 0 {
 		m.cursor = 0
@@ -382,63 +937,26 @@ func (m *Model) applyFilter() {
 	m.ensureCursorVisible()
 }
 
-func (m *Model) cycleSortMode() {
-	switch m.sortMode {
-	case "default":
-		m.sortMode = "stars"
-	case "stars":
-		m.sortMode = "name"
-	case "name":
-		m.sortMode = "updated"
-	case "updated":
-		m.sortMode = "default"
-	default:
-		m.sortMode = "default"
+// rejectReadOnly explains why actions that change the viewer's stars are
+// unavailable while browsing someone else's.
+func (m *Model) rejectReadOnly() bool {
+	if !m.readOnly {
+		return false
 	}
+	m.status = fmt.Sprintf("read-only: these are %s's stars", m.source.Login())
+	m.statusIsError = true
+	return true
+}
+
+func (m *Model) cycleSortMode() {
+	m.sortMode = search.NextSortMode(m.sortMode)
 }
 
 func (m *Model) sortFiltered() {
 	if len(m.filtered) == 0 {
 		return
 	}
-
-	sort.Slice(m.filtered, func(i, j int) bool {
-		a := m.repos[m.filtered[i]]
-		b := m.repos[m.filtered[j]]
-		switch m.sortMode {
-		case "default":
-			return a.StarredAt.After(b.StarredAt)
-		case "stars":
-			return a.Stars > b.Stars
-		case "name":
-			return strings.ToLower(a.NameWithOwner) < strings.ToLower(b.NameWithOwner)
-		case "updated":
-			return a.UpdatedAt.After(b.UpdatedAt)
-		}
-		return false
-	})
-}
-
-func matchesQuery(repo data.Repo, query string) bool {
-	if query == "" {
-		return true
-	}
-
-	haystack := strings.ToLower(strings.Join([]string{
-		repo.NameWithOwner,
-		repo.Name,
-		repo.Description,
-		repo.PrimaryLanguage,
-		strings.Join(repo.Topics, " "),
-	}, " "))
-
-	for _, term := range strings.Fields(query) {
-		if !strings.Contains(haystack, term) {
-			return false
-		}
-	}
-
-	return true
+	search.Sort(m.repos, m.filtered, m.sortMode, m.matches, m.inactiveAfter)
 }
 
 func (m *Model) moveCursor(delta int) {
@@ -479,6 +997,49 @@ func (m *Model) moveToBottom() {
 	m.offset = max(0, m.cursor-m.listBodyRows()+1)
 }
 
+// syncPreview resets the preview scroll when the selection changes and
+// schedules a debounced README load for the newly selected repo.
+func (m *Model) syncPreview() tea.Cmd {
+	repo := m.selectedRepo()
+	if repo == nil {
+		return nil
+	}
+	name := repo.NameWithOwner
+	if name != m.previewRepo {
+		m.previewRepo = name
+		m.previewOffset = 0
+	}
+	if m.previewWidth <= 0 || m.readmePending == name {
+		return nil
+	}
+	_, haveReadme := m.readmes[name]
+	_, haveDetails := m.details[name]
+	if haveReadme && haveDetails {
+		return nil
+	}
+	m.readmePending = name
+	return tea.Tick(readmeDebounce, func(time.Time) tea.Msg {
+		return readmeTickMsg{name: name}
+	})
+}
+
+func (m *Model) previewHeight() int {
+	return m.panelContentHeight(m.listHeight())
+}
+
+func (m *Model) scrollPreview(delta int) {
+	if m.previewWidth <= 0 {
+		return
+	}
+	total := len(m.previewLines(m.panelContentWidth(m.previewWidth)))
+	maxOffset := max(0, total-m.previewHeight())
+	m.previewOffset = clamp(m.previewOffset+delta, 0, maxOffset)
+}
+
+func (m *Model) matchPositions(idx int, field search.Field) []int {
+	return m.matches[idx].Positions[field]
+}
+
 func (m *Model) selectedRepo() *data.Repo {
 	if len(m.filtered) == 0 {
 		return nil
@@ -490,13 +1051,48 @@ func (m *Model) selectedRepo() *data.Repo {
 	return &m.repos[idx]
 }
 
-func fetchStarsPageCmd(client *gh.GraphQLClient, pageSize int, after *string) tea.Cmd {
+func fetchStarsPageCmd(ctx context.Context, source data.StarSource, pageSize int, after *string) tea.Cmd {
+	return fetchPageCmd(ctx, source, pageSize, after, 1, func(page data.StarsPage) tea.Msg {
+		return starsPageMsg{source: source, ctx: ctx, page: page}
+	})
+}
+
+func loadReadmeCmd(ctx context.Context, client *gh.GraphQLClient, dir, name string) tea.Cmd {
 	return func() tea.Msg {
-		page, err := data.FetchStarsPage(context.Background(), client, pageSize, after)
+		cached, cacheErr := data.LoadReadme(dir, name)
+		if cacheErr == nil && !data.ReadmeIsStale(cached, readmeTTL) {
+			return readmeMsg{name: name, readme: cached}
+		}
+
+		readme, err := data.FetchReadme(ctx, client, name)
+		if err != nil {
+			if !cached.FetchedAt.IsZero() {
+				return readmeMsg{name: name, readme: cached}
+			}
+			return readmeMsg{name: name, err: err}
+		}
+		// A failed README cache write only costs a refetch next time.
+		_ = data.SaveReadme(dir, readme)
+		return readmeMsg{name: name, readme: readme}
+	}
+}
+
+func loadDetailsCmd(ctx context.Context, client *gh.GraphQLClient, dir, name string) tea.Cmd {
+	return func() tea.Msg {
+		cached, cacheErr := data.LoadDetails(dir, name)
+		if cacheErr == nil && !data.DetailsAreStale(cached, detailsTTL) {
+			return detailsMsg{name: name, details: cached}
+		}
+
+		details, err := data.FetchDetails(ctx, client, name)
 		if err != nil {
-			return errorMsg{err: err}
+			if !cached.FetchedAt.IsZero() {
+				return detailsMsg{name: name, details: cached}
+			}
+			return detailsMsg{name: name, err: err}
 		}
-		return starsPageMsg{page: page}
+		_ = data.SaveDetails(dir, details)
+		return detailsMsg{name: name, details: details}
 	}
 }
 
@@ -513,14 +1109,17 @@ func openRepoCmd(url string) tea.Cmd {
 	}
 }
 
-func copyURLCmd(url string) tea.Cmd {
+func copyURLCmd(urls []string) tea.Cmd {
 	return func() tea.Msg {
-		if url == "" {
+		if len(urls) == 0 || urls[0] == "" {
 			return statusMsg{text: "no URL to copy", isError: true}
 		}
-		if err := clipboard.WriteAll(url); err != nil {
+		if err := clipboard.WriteAll(strings.Join(urls, "\n")); err != nil {
 			return statusMsg{text: fmt.Sprintf("copy failed: %v", err), isError: true}
 		}
+		if len(urls) > 1 {
+			return statusMsg{text: fmt.Sprintf("copied %d URLs", len(urls)), isError: false}
+		}
 		return statusMsg{text: "copied URL", isError: false}
 	}
 }
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"context"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingMutations holds the result channels of mutations whose result the
+// program has not picked up yet.
+type pendingMutations struct {
+	mu      sync.Mutex
+	results map[chan tea.Msg]struct{}
+}
+
+func (p *pendingMutations) add(result chan tea.Msg) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.results == nil {
+		p.results = make(map[chan tea.Msg]struct{})
+	}
+	p.results[result] = struct{}{}
+}
+
+func (p *pendingMutations) remove(result chan tea.Msg) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.results, result)
+}
+
+// drain returns the results that arrived but were never delivered.
+func (p *pendingMutations) drain() []tea.Msg {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	msgs := []tea.Msg{}
+	for result := range p.results {
+		select {
+		case msg := <-result:
+			msgs = append(msgs, msg)
+		default:
+		}
+		delete(p.results, result)
+	}
+	return msgs
+}
+
+// mutationCmd runs a GitHub mutation. It starts right away instead of when
+// the program gets to the command, and is tracked in m.jobs, so quitting
+// waits for it. It gets its own deadline rather than m.ctx, which is
+// cancelled on quit: an unstar that never reaches GitHub would be back on
+// the next sync.
+func (m Model) mutationCmd(mutate func(ctx context.Context) tea.Msg) tea.Cmd {
+	result := make(chan tea.Msg, 1)
+	m.mutations.add(result)
+	m.jobs.Add(1)
+	go func() {
+		defer m.jobs.Done()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(m.ctx), mutationTimeout)
+		defer cancel()
+		result <- mutate(ctx)
+	}()
+	return func() tea.Msg {
+		msg := <-result
+		m.mutations.remove(result)
+		return msg
+	}
+}
+
+// Wait blocks until background jobs such as clones and mutations have
+// stopped, then applies the mutation results the program did not get to,
+// so the cache matches GitHub. Call it after the program exits and its
+// context is cancelled.
+func (m Model) Wait() {
+	if m.jobs == nil {
+		return
+	}
+	m.jobs.Wait()
+	for _, msg := range m.mutations.drain() {
+		m, _ = m.update(msg)
+	}
+}
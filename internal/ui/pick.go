@@ -0,0 +1,25 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// pick records the selected repos (or the one under the cursor) and exits.
+// It only applies in pick mode, where the caller prints Picked() once the
+// program returns.
+func (m Model) pick() (Model, tea.Cmd) {
+	repos := m.targetRepos()
+	if len(repos) == 0 {
+		return m, nil
+	}
+	m.picked = repos
+	return m, tea.Quit
+}
+
+// Picked returns the repos chosen in pick mode, or nil if the user quit
+// without choosing.
+func (m Model) Picked() []data.Repo {
+	return m.picked
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type pickerItem struct {
+	label   string
+	detail  string
+	value   string
+	checked bool
+}
+
+// picker is a small modal list shown in place of the preview panel. Single
+// pickers submit the item under the cursor; multi pickers toggle checkboxes
+// with space and submit every checked item.
+type picker struct {
+	title    string
+	items    []pickerItem
+	cursor   int
+	multi    bool
+	onSubmit func(m Model, p picker) (Model, tea.Cmd)
+}
+
+func (p picker) selected() pickerItem {
+	if len(p.items) == 0 {
+		return pickerItem{}
+	}
+	return p.items[p.cursor]
+}
+
+func (p picker) checkedValues() []string {
+	values := []string{}
+	for _, item := range p.items {
+		if item.checked {
+			values = append(values, item.value)
+		}
+	}
+	return values
+}
+
+func (m Model) updatePicker(key string) (Model, tea.Cmd) {
+	p := *m.picker
+	switch key {
+	case "esc", "q":
+		m.picker = nil
+		return m, nil
+	case "up", "k":
+		p.cursor = max(0, p.cursor-1)
+	case "down", "j":
+		p.cursor = min(len(p.items)-1, p.cursor+1)
+	case "g":
+		p.cursor = 0
+	case "G":
+		p.cursor = max(0, len(p.items)-1)
+	case " ", "x":
+		if p.multi && len(p.items) > 0 {
+			p.items[p.cursor].checked = !p.items[p.cursor].checked
+		}
+	case "enter":
+		m.picker = nil
+		if p.onSubmit == nil || len(p.items) == 0 {
+			return m, nil
+		}
+		return p.onSubmit(m, p)
+	}
+	m.picker = &p
+	return m, nil
+}
+
+func (m Model) renderPicker(height, width int) string {
+	p := m.picker
+	lines := []string{m.styles.PanelTitle.Render(truncate(p.title, width)), ""}
+
+	rows := max(1, height-len(lines)-2)
+	offset := max(0, p.cursor-rows+1)
+
+	end := min(offset+rows, len(p.items))
+	for i := offset; i < end; i++ {
+		item := p.items[i]
+		marker := "  "
+		if i == p.cursor {
+			marker = "› "
+		}
+		label := item.label
+		if p.multi {
+			box := "[ ] "
+			if item.checked {
+				box = "[x] "
+			}
+			label = box + label
+		}
+		line := renderLineWithWidth(truncate(marker+label, width), item.detail, width)
+		if i == p.cursor {
+			line = m.styles.ListRowSelected.Render(line)
+		} else {
+			line = m.styles.ListRow.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(p.items) == 0 {
+		lines = append(lines, m.styles.Muted.Render("nothing to pick"))
+	}
+
+	hint := "↵ select · esc cancel"
+	if p.multi {
+		hint = "space toggle · ↵ apply · esc cancel"
+	}
+	if len(p.items) > rows {
+		hint = fmt.Sprintf("%d/%d · %s", p.cursor+1, len(p.items), hint)
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, m.styles.Muted.Render(truncate(hint, width)))
+
+	for i := range lines {
+		lines[i] = padRight(lines[i], width)
+	}
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	return strings.Join(lines, "\n")
+}
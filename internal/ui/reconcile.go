@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type reconcilePageMsg struct {
+	source data.StarSource
+	ctx    context.Context
+	page   data.StarsPage
+}
+
+func (m Model) startReconcile() (Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+	ctx := m.startSync()
+	m.loading = true
+	m.reconciling = true
+	m.reconciled = nil
+	m.status = "reconciling"
+	m.statusIsError = false
+	return m, tea.Batch(m.spinner.Tick, fetchReconcilePageCmd(ctx, m.source, m.pageSize, nil))
+}
+
+// handleReconcilePage collects every starred page before diffing against
+// the cache, so the list stays browsable until the full listing is in.
+func (m Model) handleReconcilePage(msg reconcilePageMsg) (Model, tea.Cmd) {
+	if !m.reconciling || msg.ctx.Err() != nil {
+		return m, nil
+	}
+
+	m.reconciled = append(m.reconciled, msg.page.Repos...)
+	if msg.page.TotalCount > 0 {
+		m.totalCount = msg.page.TotalCount
+	}
+
+	if msg.page.HasNext && msg.page.EndCursor != "" {
+		m.status = fmt.Sprintf("reconciling %d/%d", len(m.reconciled), m.totalCount)
+		next := msg.page.EndCursor
+		return m, m.throttle(msg.page.RateLimit, fetchReconcilePageCmd(m.syncCtx, m.source, m.pageSize, &next))
+	}
+
+	fetched := m.reconciled
+	m.stopSync()
+	m.reconciling = false
+	m.reconciled = nil
+	m.loading = false
+	m.applyReconcile(fetched)
+	return m, scanClonesCmd(m.cloneLayout, m.repos)
+}
+
+// applyReconcile replaces the repos with a complete starred listing, carrying
+// annotations over renames, and saves the cache.
+func (m *Model) applyReconcile(fetched []data.Repo) {
+	result := data.Reconcile(m.repos, fetched)
+	// The listing carries current metadata for every star.
+	m.hydratedAt = time.Now().UTC()
+	m.status = result.Summary()
+	m.statusIsError = false
+	if len(result.Renamed) > 0 {
+		err := m.updateAnnotations(func(annotations data.Annotations) {
+			for _, rename := range result.Renamed {
+				annotations.Rename(rename.From, rename.To)
+			}
+		})
+		if err != nil {
+			m.status = fmt.Sprintf("annotations save failed: %v", err)
+			m.statusIsError = true
+		}
+	}
+	m.replaceRepos(result.Repos)
+	m.persistCache()
+}
+
+// replaceRepos swaps in a new repo list, keeping the cursor on the same repo
+// when it is still present.
+func (m *Model) replaceRepos(repos []data.Repo) {
+	selected := ""
+	if repo := m.selectedRepo(); repo != nil {
+		selected = repo.NameWithOwner
+	}
+
+	m.repos = repos
+	m.cacheIndex = make(map[string]struct{}, len(repos))
+	for _, repo := range repos {
+		m.cacheIndex[repo.NameWithOwner] = struct{}{}
+	}
+	m.applyFilter()
+
+	for i, idx := range m.filtered {
+		if m.repos[idx].NameWithOwner == selected {
+			m.cursor = i
+			m.ensureCursorVisible()
+			break
+		}
+	}
+}
+
+func fetchReconcilePageCmd(ctx context.Context, source data.StarSource, pageSize int, after *string) tea.Cmd {
+	return fetchPageCmd(ctx, source, pageSize, after, 1, func(page data.StarsPage) tea.Msg {
+		return reconcilePageMsg{source: source, ctx: ctx, page: page}
+	})
+}
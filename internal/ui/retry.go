@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// rateLimitLow is the remaining API budget below which the footer shows it.
+const rateLimitLow = 500
+
+// retryMsg re-issues a request after a backoff or rate-limit pause.
+type retryMsg struct {
+	source data.StarSource
+	cmd    tea.Cmd
+}
+
+// fetchPageCmd fetches one page of source's stars and wraps it with done. A
+// failure carries the same request as the next attempt, so the error handler
+// can decide whether to retry it.
+func fetchPageCmd(ctx context.Context, source data.StarSource, pageSize int, after *string, attempt int, done func(data.StarsPage) tea.Msg) tea.Cmd {
+	if source == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		page, err := source.FetchStarsPage(ctx, pageSize, after)
+		if err != nil {
+			return errorMsg{
+				source:  source,
+				ctx:     ctx,
+				err:     err,
+				attempt: attempt,
+				retry:   fetchPageCmd(ctx, source, pageSize, after, attempt+1, done),
+			}
+		}
+		return done(page)
+	}
+}
+
+// later runs cmd after d, unless the tab it belongs to is closed by then.
+func (m Model) later(d time.Duration, cmd tea.Cmd) tea.Cmd {
+	source := m.source
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return retryMsg{source: source, cmd: cmd}
+	})
+}
+
+// throttle runs the request for the next page right away, or once the rate
+// limit resets when the last page used up the budget.
+func (m *Model) throttle(limit data.RateLimit, next tea.Cmd) tea.Cmd {
+	m.rateLimit = limit
+	wait := limit.Wait(time.Now())
+	if wait <= 0 {
+		return next
+	}
+	m.status = "rate limit reached · resuming at " + limit.ResetAt.Local().Format("15:04")
+	m.statusIsError = true
+	return m.later(wait, next)
+}
+
+// handleFetchError retries transient failures. Anything else ends the sync,
+// leaving whatever is cached browsable; only a first load with nothing to
+// show is fatal. Errors of a cancelled sync are expected and dropped.
+func (m Model) handleFetchError(msg errorMsg) (Model, tea.Cmd) {
+	if msg.ctx != nil && msg.ctx.Err() != nil {
+		return m, nil
+	}
+	if delay, ok := data.RetryDelay(msg.err, msg.attempt, time.Now()); ok && msg.retry != nil {
+		m.status = fmt.Sprintf("%s · retry %d/%d in %s", firstLine(msg.err.Error()), msg.attempt, data.MaxRetries, delay.Round(time.Second))
+		m.statusIsError = true
+		return m, m.later(delay, msg.retry)
+	}
+
+	m.abortSync()
+	if len(m.repos) == 0 && m.activeTab == 0 {
+		m.err = msg.err
+	}
+	m.status = "sync failed: " + firstLine(msg.err.Error())
+	if len(m.repos) > 0 {
+		m.status += " · showing cached stars"
+	}
+	m.statusIsError = true
+	return m, nil
+}
+
+func (m Model) rateLimitText() string {
+	if m.rateLimit.ResetAt.IsZero() || m.rateLimit.Remaining >= rateLimitLow {
+		return ""
+	}
+	return fmt.Sprintf("[API: %d left until %s]", m.rateLimit.Remaining, m.rateLimit.ResetAt.Local().Format("15:04"))
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
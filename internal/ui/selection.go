@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// bulkOpenConfirm is how many browser tabs can be opened without asking.
+const bulkOpenConfirm = 5
+
+func (m *Model) isSelected(name string) bool {
+	_, ok := m.selection[name]
+	return ok
+}
+
+func (m *Model) toggleSelected() {
+	repo := m.selectedRepo()
+	if repo == nil {
+		return
+	}
+	if m.isSelected(repo.NameWithOwner) {
+		delete(m.selection, repo.NameWithOwner)
+	} else {
+		m.selection[repo.NameWithOwner] = struct{}{}
+	}
+	m.moveCursor(1)
+}
+
+// selectRange starts a range at the cursor on the first press and selects
+// every row between it and the cursor on the second.
+func (m *Model) selectRange() {
+	if len(m.filtered) == 0 {
+		return
+	}
+	if m.rangeAnchor == "" {
+		if repo := m.selectedRepo(); repo != nil {
+			m.rangeAnchor = repo.NameWithOwner
+			m.status = "range started · move and press again to select"
+			m.statusIsError = false
+		}
+		return
+	}
+
+	anchor := -1
+	for i, idx := range m.filtered {
+		if m.repos[idx].NameWithOwner == m.rangeAnchor {
+			anchor = i
+			break
+		}
+	}
+	m.rangeAnchor = ""
+	if anchor < 0 {
+		m.status = "range start is no longer visible"
+		m.statusIsError = false
+		return
+	}
+
+	from, to := min(anchor, m.cursor), max(anchor, m.cursor)
+	for _, idx := range m.filtered[from : to+1] {
+		m.selection[m.repos[idx].NameWithOwner] = struct{}{}
+	}
+	m.status = fmt.Sprintf("%d selected", len(m.selection))
+	m.statusIsError = false
+}
+
+func (m *Model) selectAllFiltered() {
+	for _, idx := range m.filtered {
+		m.selection[m.repos[idx].NameWithOwner] = struct{}{}
+	}
+	m.status = fmt.Sprintf("%d selected", len(m.selection))
+	m.statusIsError = false
+}
+
+func (m *Model) clearSelection() {
+	m.selection = make(map[string]struct{})
+	m.rangeAnchor = ""
+}
+
+// targetRepos is what actions apply to: the selection when there is one,
+// otherwise the repo under the cursor. Selected repos come in display order,
+// followed by any hidden by the current filter.
+func (m *Model) targetRepos() []data.Repo {
+	if len(m.selection) == 0 {
+		if repo := m.selectedRepo(); repo != nil {
+			return []data.Repo{*repo}
+		}
+		return nil
+	}
+
+	repos := make([]data.Repo, 0, len(m.selection))
+	seen := make(map[string]struct{}, len(m.selection))
+	for _, idx := range m.filtered {
+		repo := m.repos[idx]
+		if m.isSelected(repo.NameWithOwner) {
+			repos = append(repos, repo)
+			seen[repo.NameWithOwner] = struct{}{}
+		}
+	}
+	for _, repo := range m.repos {
+		if _, ok := seen[repo.NameWithOwner]; ok {
+			continue
+		}
+		if m.isSelected(repo.NameWithOwner) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+func (m Model) openRepos(repos []data.Repo) (Model, tea.Cmd) {
+	open := func(m Model) (Model, tea.Cmd) {
+		cmds := make([]tea.Cmd, 0, len(repos))
+		for _, repo := range repos {
+			cmds = append(cmds, openRepoCmd(repo.URL))
+		}
+		return m, tea.Batch(cmds...)
+	}
+	if len(repos) > bulkOpenConfirm {
+		m.prompt = &confirmPrompt{
+			text:      fmt.Sprintf("open %d repositories in the browser?", len(repos)),
+			onConfirm: open,
+		}
+		return m, nil
+	}
+	return open(m)
+}
+
+func repoURLs(repos []data.Repo) []string {
+	urls := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		urls = append(urls, repo.URL)
+	}
+	return urls
+}
+
+// openBulkListAdd adds repos to the chosen lists, keeping the lists each one
+// already belongs to.
+func (m *Model) openBulkListAdd(repos []data.Repo) {
+	if len(m.lists) == 0 {
+		m.status = "no star lists"
+		m.statusIsError = false
+		return
+	}
+
+	items := make([]pickerItem, 0, len(m.lists))
+	for _, list := range m.lists {
+		items = append(items, pickerItem{label: list.Name, detail: fmt.Sprintf("%d", len(list.Repos)), value: list.ID})
+	}
+
+	m.picker = &picker{
+		title: fmt.Sprintf("Add %d repos to lists", len(repos)),
+		items: items,
+		multi: true,
+		onSubmit: func(m Model, p picker) (Model, tea.Cmd) {
+			add := p.checkedValues()
+			if len(add) == 0 {
+				return m, nil
+			}
+			cmds := make([]tea.Cmd, 0, len(repos))
+			for _, repo := range repos {
+				previous := m.lists
+				listIDs := append(repoListIDs(m.lists, repo.NameWithOwner), add...)
+				m.lists = data.WithRepoLists(m.lists, repo.NameWithOwner, dedupe(listIDs))
+				cmds = append(cmds, m.setRepoListsCmd(repo, dedupe(listIDs), previous))
+			}
+			m.setLists(m.lists)
+			m.status = fmt.Sprintf("adding %d repos to %s", len(repos), strings.Join(listNamesByID(m.lists, add), ", "))
+			m.statusIsError = false
+			return m, tea.Batch(cmds...)
+		},
+	}
+}
+
+func repoListIDs(lists []data.StarList, name string) []string {
+	ids := []string{}
+	for _, list := range lists {
+		if list.Contains(name) {
+			ids = append(ids, list.ID)
+		}
+	}
+	return ids
+}
+
+func listNamesByID(lists []data.StarList, ids []string) []string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		for _, list := range lists {
+			if list.ID == id {
+				names = append(names, list.Name)
+			}
+		}
+	}
+	return names
+}
+
+func dedupe(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	return out
+}
@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+type removedRepo struct {
+	repo  data.Repo
+	index int
+}
+
+type undoState struct {
+	removed []removedRepo
+	expires time.Time
+}
+
+type starResultMsg struct {
+	repo    data.Repo
+	starred bool
+	err     error
+}
+
+type undoExpiredMsg struct {
+	expires time.Time
+}
+
+func (m *Model) confirmUnstar(repos []data.Repo) {
+	if len(repos) == 0 {
+		return
+	}
+	text := fmt.Sprintf("unstar %s?", repos[0].NameWithOwner)
+	if len(repos) > 1 {
+		text = fmt.Sprintf("unstar %d repositories?", len(repos))
+	}
+	m.prompt = &confirmPrompt{
+		text: text,
+		onConfirm: func(m Model) (Model, tea.Cmd) {
+			return m.unstar(repos)
+		},
+	}
+}
+
+// unstar optimistically drops repos from the list, then fires the mutations.
+// The cache follows once GitHub confirms. The removal can be reverted with
+// undo until the window expires.
+func (m Model) unstar(repos []data.Repo) (Model, tea.Cmd) {
+	names := make(map[string]struct{}, len(repos))
+	for _, repo := range repos {
+		names[repo.NameWithOwner] = struct{}{}
+	}
+
+	removed := make([]removedRepo, 0, len(repos))
+	kept := make([]data.Repo, 0, len(m.repos))
+	for i, repo := range m.repos {
+		if _, ok := names[repo.NameWithOwner]; ok {
+			removed = append(removed, removedRepo{repo: repo, index: i})
+			delete(m.cacheIndex, repo.NameWithOwner)
+			delete(m.selection, repo.NameWithOwner)
+			m.unstarring[repo.NameWithOwner] = true
+			continue
+		}
+		kept = append(kept, repo)
+	}
+	if len(removed) == 0 {
+		return m, nil
+	}
+
+	m.repos = kept
+	m.applyFilter()
+
+	expires := time.Now().Add(undoWindow)
+	m.undo = &undoState{removed: removed, expires: expires}
+	if !m.statusIsError {
+		m.status = fmt.Sprintf("unstarred %s · z to undo", describeRepos(removed))
+	}
+
+	cmds := make([]tea.Cmd, 0, len(removed)+1)
+	for _, entry := range removed {
+		cmds = append(cmds, m.setStarCmd(entry.repo, false))
+	}
+	cmds = append(cmds, tea.Tick(undoWindow, func(time.Time) tea.Msg {
+		return undoExpiredMsg{expires: expires}
+	}))
+	return m, tea.Batch(cmds...)
+}
+
+// undoUnstar puts the last unstarred repos back where they were and stars
+// them again. Repos whose removal is still in flight are re-starred once the
+// removal completes, so the two mutations cannot race.
+func (m Model) undoUnstar() (Model, tea.Cmd) {
+	if m.undo == nil || time.Now().After(m.undo.expires) {
+		m.undo = nil
+		m.status = "nothing to undo"
+		m.statusIsError = false
+		return m, nil
+	}
+
+	removed := m.undo.removed
+	m.undo = nil
+	m.restoreRepos(removed)
+	m.status = fmt.Sprintf("restored %s", describeRepos(removed))
+	m.statusIsError = false
+
+	cmds := []tea.Cmd{}
+	for _, entry := range removed {
+		name := entry.repo.NameWithOwner
+		if m.unstarring[name] {
+			m.restarQueued[name] = true
+			continue
+		}
+		cmds = append(cmds, m.setStarCmd(entry.repo, true))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m *Model) restoreRepos(removed []removedRepo) {
+	sorted := append([]removedRepo(nil), removed...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].index < sorted[j].index })
+
+	for _, entry := range sorted {
+		if _, exists := m.cacheIndex[entry.repo.NameWithOwner]; exists {
+			continue
+		}
+		idx := clamp(entry.index, 0, len(m.repos))
+		m.repos = append(m.repos, data.Repo{})
+		copy(m.repos[idx+1:], m.repos[idx:])
+		m.repos[idx] = entry.repo
+		m.cacheIndex[entry.repo.NameWithOwner] = struct{}{}
+	}
+	m.applyFilter()
+}
+
+func (m Model) handleStarResult(msg starResultMsg) (Model, tea.Cmd) {
+	name := msg.repo.NameWithOwner
+
+	if msg.starred {
+		if msg.err != nil {
+			m.status = fmt.Sprintf("re-star %s failed: %v", name, msg.err)
+			m.statusIsError = true
+			return m, nil
+		}
+		m.persistCache()
+		return m, nil
+	}
+
+	delete(m.unstarring, name)
+	if m.restarQueued[name] {
+		delete(m.restarQueued, name)
+		if msg.err != nil {
+			// The star was never removed, so there is nothing to put back.
+			return m, nil
+		}
+		return m, m.setStarCmd(msg.repo, true)
+	}
+
+	if msg.err != nil {
+		entry := removedRepo{repo: msg.repo}
+		if m.undo != nil {
+			for _, candidate := range m.undo.removed {
+				if candidate.repo.NameWithOwner == name {
+					entry = candidate
+				}
+			}
+			m.undo.removed = withoutRepo(m.undo.removed, name)
+		}
+		m.restoreRepos([]removedRepo{entry})
+		m.persistCache()
+		m.status = fmt.Sprintf("unstar %s failed: %v", name, msg.err)
+		m.statusIsError = true
+		return m, nil
+	}
+	m.persistCache()
+	return m, nil
+}
+
+func withoutRepo(removed []removedRepo, name string) []removedRepo {
+	out := removed[:0]
+	for _, entry := range removed {
+		if entry.repo.NameWithOwner != name {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+func describeRepos(removed []removedRepo) string {
+	if len(removed) == 1 {
+		return removed[0].repo.NameWithOwner
+	}
+	names := make([]string, 0, len(removed))
+	for _, entry := range removed {
+		names = append(names, entry.repo.Name)
+	}
+	return fmt.Sprintf("%d repos (%s)", len(removed), truncate(strings.Join(names, ", "), 40))
+}
+
+func (m Model) setStarCmd(repo data.Repo, starred bool) tea.Cmd {
+	client := m.client
+	return m.mutationCmd(func(ctx context.Context) tea.Msg {
+		var err error
+		if starred {
+			err = data.AddStar(ctx, client, repo)
+		} else {
+			err = data.RemoveStar(ctx, client, repo)
+		}
+		return starResultMsg{repo: repo, starred: starred, err: err}
+	})
+}
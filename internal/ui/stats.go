@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/stats"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var barEighths = []rune(" ▏▎▍▌▋▊▉")
+
+func (m *Model) toggleStats() {
+	m.showStats = !m.showStats
+	m.statsOffset = 0
+}
+
+func (m *Model) scrollStats(delta int) {
+	total := len(m.statsLines(m.panelContentWidth(m.width)))
+	maxOffset := max(0, total-m.panelContentHeight(m.listHeight()))
+	m.statsOffset = clamp(m.statsOffset+delta, 0, maxOffset)
+}
+
+func (m Model) renderStats(height, width int) string {
+	lines := m.statsLines(width)
+	offset := clamp(m.statsOffset, 0, max(0, len(lines)-height))
+	lines = lines[offset:]
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for i := range lines {
+		lines[i] = padRight(lines[i], width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) statsLines(width int) []string {
+	summary := stats.Compute(m.repos, time.Now(), m.inactiveAfter)
+	if summary.Total == 0 {
+		return []string{m.styles.Muted.Render("no stars cached yet")}
+	}
+
+	lines := []string{m.styles.PreviewTitle.Render("Stars overview"), ""}
+	overview := fmt.Sprintf("%d repos · %d forks (%s) · %d archived (%s) · %d without a push in over %s (%s)",
+		summary.Total, summary.Forks, percent(summary.Forks, summary.Total),
+		summary.Archived, percent(summary.Archived, summary.Total),
+		summary.Stale, humanizeThreshold(m.inactiveAfter), percent(summary.Stale, summary.Total))
+	for _, line := range wrapLines([]string{overview}, width) {
+		lines = append(lines, m.styles.Muted.Render(line))
+	}
+	lines = append(lines, "")
+	lines = append(lines, m.monthLines(summary.Months, width)...)
+	lines = append(lines, "")
+
+	sections := []struct {
+		title  string
+		counts []stats.Count
+	}{
+		{"Languages", summary.Languages},
+		{"Topics", summary.Topics},
+		{"Owners", summary.Owners},
+		{"Stargazers", summary.StarRange},
+	}
+
+	// Side by side when there is room for two readable charts.
+	if width >= 90 {
+		columnWidth := (width - panelGap) / 2
+		for i := 0; i+1 < len(sections); i += 2 {
+			left := m.barSection(sections[i].title, sections[i].counts, columnWidth)
+			right := m.barSection(sections[i+1].title, sections[i+1].counts, columnWidth)
+			rows := max(len(left), len(right))
+			joined := joinColumns(strings.Join(left, "\n"), strings.Join(right, "\n"), strings.Repeat(" ", panelGap), rows, columnWidth, columnWidth)
+			lines = append(lines, strings.Split(joined, "\n")...)
+			lines = append(lines, "")
+		}
+		return lines
+	}
+
+	for _, section := range sections {
+		lines = append(lines, m.barSection(section.title, section.counts, width)...)
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func (m Model) monthLines(months []stats.Month, width int) []string {
+	lines := []string{m.styles.PanelTitle.Render("Starred per month")}
+
+	values := make([]int, len(months))
+	total := 0
+	for i, month := range months {
+		values[i] = month.Count
+		total += month.Count
+	}
+
+	cell := clamp(width/max(1, len(months)), 1, 3)
+	lines = append(lines, m.styles.Tag.Render(sparkline(values, cell)))
+
+	first := months[0].Start.Format("Jan 2006")
+	last := months[len(months)-1].Start.Format("Jan 2006")
+	axis := renderLineWithWidth(first, last, cell*len(months))
+	lines = append(lines, m.styles.Muted.Render(axis))
+	lines = append(lines, m.styles.Muted.Render(fmt.Sprintf("%d starred in the last %d months", total, len(months))))
+	return lines
+}
+
+func (m Model) barSection(title string, counts []stats.Count, width int) []string {
+	lines := []string{m.styles.PanelTitle.Render(title)}
+	if len(counts) == 0 {
+		return append(lines, m.styles.Muted.Render("none"))
+	}
+
+	labelWidth := 0
+	countWidth := 0
+	peak := 0
+	for _, c := range counts {
+		labelWidth = max(labelWidth, len([]rune(c.Name)))
+		countWidth = max(countWidth, len(fmt.Sprint(c.Count)))
+		peak = max(peak, c.Count)
+	}
+	labelWidth = min(labelWidth, max(6, width/3))
+	barWidth := max(1, width-labelWidth-countWidth-2)
+
+	for _, c := range counts {
+		label := padRight(truncate(c.Name, labelWidth), labelWidth)
+		bar := padRight(bar(c.Count, peak, barWidth), barWidth)
+		lines = append(lines, label+" "+m.styles.Tag.Render(bar)+" "+m.styles.Muted.Render(fmt.Sprintf("%*d", countWidth, c.Count)))
+	}
+	return lines
+}
+
+// bar draws value relative to peak in width cells, using eighth blocks for
+// the fractional part.
+func bar(value, peak, width int) string {
+	if peak <= 0 || value <= 0 {
+		return ""
+	}
+	eighths := value * width * 8 / peak
+	if eighths == 0 {
+		eighths = 1
+	}
+	full := eighths / 8
+	s := strings.Repeat("█", full)
+	if rest := eighths % 8; rest > 0 {
+		s += string(barEighths[rest])
+	}
+	return s
+}
+
+func sparkline(values []int, cell int) string {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		r := ' '
+		if v > 0 && peak > 0 {
+			r = sparkBlocks[min(len(sparkBlocks)-1, v*(len(sparkBlocks)-1)/peak)]
+		}
+		b.WriteString(strings.Repeat(string(r), cell))
+	}
+	return b.String()
+}
+
+func percent(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(part)*100/float64(total))
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+func TestStatsOverviewThreshold(t *testing.T) {
+	now := time.Now()
+	cache := data.Cache{Repos: []data.Repo{
+		{NameWithOwner: "a/fresh", PushedAt: now.AddDate(0, -1, 0)},
+		{NameWithOwner: "a/quiet", PushedAt: now.AddDate(0, -6, 0)},
+		{NameWithOwner: "a/old", PushedAt: now.AddDate(-2, 0, 0)},
+	}}
+
+	tests := []struct {
+		after time.Duration
+		want  string
+	}{
+		{0, "1 without a push in over a year"},
+		{90 * 24 * time.Hour, "2 without a push in over 3 months"},
+		{3 * data.DefaultInactiveAfter, "0 without a push in over 3 years"},
+	}
+
+	for _, tt := range tests {
+		m := NewModel(nil, cache, Options{InactiveAfter: tt.after})
+		overview := strings.Join(m.statsLines(200), "\n")
+		if !strings.Contains(overview, tt.want) {
+			t.Errorf("overview after %s does not say %q:\n%s", tt.after, tt.want, overview)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 type Styles struct {
 	HeaderBar                lipgloss.Style
@@ -16,7 +22,14 @@ type Styles struct {
 	ListRowSecondary         lipgloss.Style
 	ListRowSelected          lipgloss.Style
 	ListRowSelectedSecondary lipgloss.Style
+	SelectionMarker          lipgloss.Style
 	PreviewTitle             lipgloss.Style
+	MatchHighlight           lipgloss.Style
+	Tag                      lipgloss.Style
+	MarkdownHeading          lipgloss.Style
+	MarkdownCode             lipgloss.Style
+	MarkdownLink             lipgloss.Style
+	MarkdownQuote            lipgloss.Style
 	Muted                    lipgloss.Style
 	Footer                   lipgloss.Style
 	FooterKey                lipgloss.Style
@@ -32,6 +45,7 @@ func DefaultStyles() Styles {
 	success := lipgloss.AdaptiveColor{Light: "#059669", Dark: "#50FA7B"}   // green
 	border := lipgloss.AdaptiveColor{Light: "#D1D5DB", Dark: "#44475A"}
 	text := lipgloss.AdaptiveColor{Light: "#1F2937", Dark: "#F8F8F2"}
+	highlight := lipgloss.AdaptiveColor{Light: "#B45309", Dark: "#F1FA8C"} // yellow
 
 	return Styles{
 		HeaderBar:                lipgloss.NewStyle(),
@@ -47,7 +61,14 @@ func DefaultStyles() Styles {
 		ListRowSecondary:         lipgloss.NewStyle().Foreground(muted),
 		ListRowSelected:          lipgloss.NewStyle().Foreground(success).Bold(true),
 		ListRowSelectedSecondary: lipgloss.NewStyle().Foreground(info),
+		SelectionMarker:          lipgloss.NewStyle().Foreground(accentAlt).Bold(true),
 		PreviewTitle:             lipgloss.NewStyle().Bold(true).Foreground(accent),
+		MatchHighlight:           lipgloss.NewStyle().Foreground(highlight).Underline(true),
+		Tag:                      lipgloss.NewStyle().Foreground(accentAlt),
+		MarkdownHeading:          lipgloss.NewStyle().Bold(true).Foreground(accentAlt).Underline(true),
+		MarkdownCode:             lipgloss.NewStyle().Foreground(info),
+		MarkdownLink:             lipgloss.NewStyle().Foreground(accent).Underline(true),
+		MarkdownQuote:            lipgloss.NewStyle().Foreground(muted).Italic(true),
 		Muted:                    lipgloss.NewStyle().Foreground(muted),
 		Footer:                   lipgloss.NewStyle().Foreground(muted),
 		FooterKey:                lipgloss.NewStyle().Foreground(accent).Bold(true),
@@ -55,3 +76,111 @@ func DefaultStyles() Styles {
 		Divider:                  lipgloss.NewStyle().Foreground(border),
 	}
 }
+
+// Color is a terminal color. Dark is used on dark backgrounds; when empty,
+// Light is used everywhere.
+type Color struct {
+	Light string
+	Dark  string
+}
+
+func (c Color) terminalColor() lipgloss.TerminalColor {
+	if c.Dark == "" || c.Dark == c.Light {
+		return lipgloss.Color(c.Light)
+	}
+	return lipgloss.AdaptiveColor{Light: c.Light, Dark: c.Dark}
+}
+
+// StyleOverride changes selected properties of one style. Nil fields keep
+// the default.
+type StyleOverride struct {
+	Foreground       *Color
+	Background       *Color
+	BorderForeground *Color
+	Bold             *bool
+	Italic           *bool
+	Underline        *bool
+	Faint            *bool
+}
+
+func (o StyleOverride) apply(style lipgloss.Style) lipgloss.Style {
+	if o.Foreground != nil {
+		style = style.Foreground(o.Foreground.terminalColor())
+	}
+	if o.Background != nil {
+		style = style.Background(o.Background.terminalColor())
+	}
+	if o.BorderForeground != nil {
+		style = style.BorderForeground(o.BorderForeground.terminalColor())
+	}
+	if o.Bold != nil {
+		style = style.Bold(*o.Bold)
+	}
+	if o.Italic != nil {
+		style = style.Italic(*o.Italic)
+	}
+	if o.Underline != nil {
+		style = style.Underline(*o.Underline)
+	}
+	if o.Faint != nil {
+		style = style.Faint(*o.Faint)
+	}
+	return style
+}
+
+func (s *Styles) byName() map[string]*lipgloss.Style {
+	return map[string]*lipgloss.Style{
+		"header_bar":                  &s.HeaderBar,
+		"header_title":                &s.HeaderTitle,
+		"header_meta":                 &s.HeaderMeta,
+		"search_prompt":               &s.SearchPrompt,
+		"search_text":                 &s.SearchText,
+		"search_inactive":             &s.SearchInactive,
+		"search_box":                  &s.SearchBox,
+		"panel":                       &s.Panel,
+		"panel_title":                 &s.PanelTitle,
+		"list_row":                    &s.ListRow,
+		"list_row_secondary":          &s.ListRowSecondary,
+		"list_row_selected":           &s.ListRowSelected,
+		"list_row_selected_secondary": &s.ListRowSelectedSecondary,
+		"selection_marker":            &s.SelectionMarker,
+		"preview_title":               &s.PreviewTitle,
+		"match_highlight":             &s.MatchHighlight,
+		"tag":                         &s.Tag,
+		"markdown_heading":            &s.MarkdownHeading,
+		"markdown_code":               &s.MarkdownCode,
+		"markdown_link":               &s.MarkdownLink,
+		"markdown_quote":              &s.MarkdownQuote,
+		"muted":                       &s.Muted,
+		"footer":                      &s.Footer,
+		"footer_key":                  &s.FooterKey,
+		"footer_error":                &s.FooterError,
+		"divider":                     &s.Divider,
+	}
+}
+
+// StyleNames lists the keys accepted by WithTheme.
+func StyleNames() []string {
+	var s Styles
+	fields := s.byName()
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithTheme returns s with the overrides applied, keyed by the snake_case
+// name of each Styles field.
+func (s Styles) WithTheme(theme map[string]StyleOverride) (Styles, error) {
+	fields := s.byName()
+	for name, override := range theme {
+		style, ok := fields[name]
+		if !ok {
+			return s, fmt.Errorf("unknown style %q (want one of %s)", name, strings.Join(StyleNames(), ", "))
+		}
+		*style = override.apply(*style)
+	}
+	return s, nil
+}
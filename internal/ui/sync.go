@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startSync gives a refresh or full sync of the current tab its own context,
+// so it can be cancelled without touching the rest of the UI. The repos shown
+// now are what an aborted sync falls back to.
+func (m *Model) startSync() context.Context {
+	m.stopSync()
+	m.syncCtx, m.cancelSync = context.WithCancel(m.ctx)
+	m.syncBase = m.repos
+	return m.syncCtx
+}
+
+// stopSync cancels the current tab's sync, if any.
+func (m *Model) stopSync() {
+	if m.cancelSync != nil {
+		m.cancelSync()
+	}
+	m.cancelSync = nil
+	m.syncBase = nil
+}
+
+// abortSync ends a sync that did not complete. Pages fetched so far are
+// dropped again for cached sources: persisting part of a listing would make
+// the next refresh stop early and miss the rest.
+func (m *Model) abortSync() {
+	base := m.syncBase
+	m.stopSync()
+	m.loading = false
+	m.reconciling = false
+	m.reconciled = nil
+	m.deferRefresh = false
+	m.pendingNew = nil
+	if m.cachePath != "" {
+		m.cacheDirty = false
+		m.replaceRepos(base)
+	} else {
+		m.applyFilter()
+	}
+}
+
+func (m Model) cancelSyncAction() (Model, tea.Cmd) {
+	if !m.loading {
+		return m, nil
+	}
+	m.abortSync()
+	m.status = "sync cancelled"
+	m.statusIsError = false
+	return m, nil
+}
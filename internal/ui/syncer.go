@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// Syncer is the only writer of a source's cache file. It also runs the
+// background sync of a stale cache, feeding the result into the program for
+// the model to merge; the model then hands the merged cache back to Save.
+type Syncer struct {
+	source   data.StarSource
+	path     string
+	pageSize int
+
+	mu      sync.Mutex
+	base    data.Cache
+	running sync.WaitGroup
+}
+
+// syncedMsg carries the result of a background sync. A reconciling sync
+// lists every star, otherwise only those newer than the cache.
+type syncedMsg struct {
+	source    data.StarSource
+	repos     []data.Repo
+	reconcile bool
+	err       error
+}
+
+func NewSyncer(source data.StarSource, path string, pageSize int) *Syncer {
+	return &Syncer{source: source, path: path, pageSize: pageSize}
+}
+
+// Start syncs cached against GitHub in the background and sends the result
+// to program. Cancelling ctx aborts it.
+func (s *Syncer) Start(ctx context.Context, program *tea.Program, cached []data.Repo, reconcile bool) {
+	if s.path == "" || s.source == nil {
+		return
+	}
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		msg := syncedMsg{source: s.source, reconcile: reconcile}
+		if reconcile {
+			msg.repos, msg.err = data.FetchAllStars(ctx, s.source, s.pageSize)
+		} else {
+			msg.repos, msg.err = data.FetchNewStars(ctx, s.source, s.pageSize, cached)
+		}
+		program.Send(msg)
+	}()
+}
+
+// Load reads the cache and remembers it as the base of the next Save.
+func (s *Syncer) Load() (data.Cache, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cache, err := data.LoadCache(s.path)
+	if err == nil {
+		s.base = cache
+	}
+	return cache, err
+}
+
+// Save writes cache to the syncer's file, one write at a time. Changes
+// another instance saved since the last Load or Save are merged in, in which
+// case merged is true and saved differs from cache.
+func (s *Syncer) Save(cache data.Cache) (saved data.Cache, merged bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved, merged, err = data.UpdateCache(s.path, s.base, cache)
+	if err == nil {
+		s.base = saved
+	}
+	return saved, merged, err
+}
+
+// Wait blocks until the background sync has stopped.
+func (s *Syncer) Wait() {
+	s.running.Wait()
+}
+
+// handleSynced merges a background sync into the repos and saves the cache.
+// A sync started from the UI in the meantime supersedes it.
+func (m Model) handleSynced(msg syncedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.status = "background sync failed: " + firstLine(msg.err.Error()) + " · showing cached stars"
+		m.statusIsError = true
+		return m, nil
+	}
+	if m.loading {
+		return m, nil
+	}
+
+	if msg.reconcile {
+		m.applyReconcile(msg.repos)
+		return m, scanClonesCmd(m.cloneLayout, m.repos)
+	}
+
+	added := make([]data.Repo, 0, len(msg.repos))
+	for _, repo := range msg.repos {
+		if _, exists := m.cacheIndex[repo.NameWithOwner]; !exists {
+			added = append(added, repo)
+		}
+	}
+	if len(added) > 0 {
+		m.replaceRepos(append(added, m.repos...))
+	}
+	m.status = "synced · up to date"
+	if len(added) > 0 {
+		m.status = fmt.Sprintf("synced · %d new", len(added))
+	}
+	m.statusIsError = false
+	m.persistCache()
+	return m, scanClonesCmd(m.cloneLayout, m.repos)
+}
@@ -0,0 +1,407 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	gh "github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+// starTab holds the per-source state of a tab while another tab is active.
+// The first tab is always the stars the UI was started with; the others
+// browse someone else's stars and are read-only.
+type starTab struct {
+	source            data.StarSource
+	readOnly          bool
+	repos             []data.Repo
+	cacheIndex        map[string]struct{}
+	cachePath         string
+	totalCount        int
+	nextCursor        *string
+	loading           bool
+	deferRefresh      bool
+	pendingNew        []data.Repo
+	cacheDirty        bool
+	reconciling       bool
+	reconciled        []data.Repo
+	syncCtx           context.Context
+	cancelSync        context.CancelFunc
+	syncBase          []data.Repo
+	lists             []data.StarList
+	activeListID      string
+	activeListMembers map[string]struct{}
+	cursor            int
+	offset            int
+	selection         map[string]struct{}
+	rangeAnchor       string
+}
+
+type ownerMsg struct {
+	owner data.Owner
+	err   error
+}
+
+type starTooMsg struct {
+	repo data.Repo
+	err  error
+}
+
+func (m *Model) saveTab() {
+	m.tabs[m.activeTab] = starTab{
+		source:            m.source,
+		readOnly:          m.readOnly,
+		repos:             m.repos,
+		cacheIndex:        m.cacheIndex,
+		cachePath:         m.cachePath,
+		totalCount:        m.totalCount,
+		nextCursor:        m.nextCursor,
+		loading:           m.loading,
+		deferRefresh:      m.deferRefresh,
+		pendingNew:        m.pendingNew,
+		cacheDirty:        m.cacheDirty,
+		reconciling:       m.reconciling,
+		reconciled:        m.reconciled,
+		syncCtx:           m.syncCtx,
+		cancelSync:        m.cancelSync,
+		syncBase:          m.syncBase,
+		lists:             m.lists,
+		activeListID:      m.activeListID,
+		activeListMembers: m.activeListMembers,
+		cursor:            m.cursor,
+		offset:            m.offset,
+		selection:         m.selection,
+		rangeAnchor:       m.rangeAnchor,
+	}
+}
+
+func (m *Model) loadTab(i int) {
+	t := m.tabs[i]
+	m.activeTab = i
+	m.source = t.source
+	m.readOnly = t.readOnly
+	m.repos = t.repos
+	m.cacheIndex = t.cacheIndex
+	m.cachePath = t.cachePath
+	m.totalCount = t.totalCount
+	m.nextCursor = t.nextCursor
+	m.loading = t.loading
+	m.deferRefresh = t.deferRefresh
+	m.pendingNew = t.pendingNew
+	m.cacheDirty = t.cacheDirty
+	m.reconciling = t.reconciling
+	m.reconciled = t.reconciled
+	m.syncCtx = t.syncCtx
+	m.cancelSync = t.cancelSync
+	m.syncBase = t.syncBase
+	m.lists = t.lists
+	m.activeListID = t.activeListID
+	m.activeListMembers = t.activeListMembers
+	m.cursor = t.cursor
+	m.offset = t.offset
+	m.selection = t.selection
+	m.rangeAnchor = t.rangeAnchor
+}
+
+func (m *Model) switchTab(i int) {
+	if len(m.tabs) < 2 {
+		return
+	}
+	i = (i + len(m.tabs)) % len(m.tabs)
+	if i == m.activeTab {
+		return
+	}
+	m.saveTab()
+	m.loadTab(i)
+	m.previewOffset = 0
+	m.applyFilter()
+	m.status = "viewing " + m.tabLabel(i)
+	m.statusIsError = false
+}
+
+// onTab runs update against tab i as if it were active, for messages that
+// arrive for a tab in the background. The active tab's status is kept unless
+// the background tab reports an error.
+func (m Model) onTab(i int, update func(Model) (Model, tea.Cmd)) (Model, tea.Cmd) {
+	if i == m.activeTab {
+		return update(m)
+	}
+	active := m.activeTab
+	status, isError := m.status, m.statusIsError
+	m.saveTab()
+	m.loadTab(i)
+	m, cmd := update(m)
+	m.saveTab()
+	m.loadTab(active)
+	m.applyFilter()
+	if !m.statusIsError {
+		m.status, m.statusIsError = status, isError
+	}
+	return m, cmd
+}
+
+// messageTab reports which tab msg belongs to, or false for messages that
+// are not tied to a tab. Metadata, list and star updates always concern the
+// viewer's own stars in the first tab.
+func (m Model) messageTab(msg tea.Msg) (int, bool) {
+	switch msg := msg.(type) {
+	case starsPageMsg:
+		return m.tabOf(msg.source), true
+	case reconcilePageMsg:
+		return m.tabOf(msg.source), true
+	case errorMsg:
+		return m.tabOf(msg.source), true
+	case retryMsg:
+		return m.tabOf(msg.source), true
+	case syncedMsg:
+		return m.tabOf(msg.source), true
+	case hydrateTickMsg, hydratedMsg, listsMsg, listsUpdatedMsg, starResultMsg:
+		return 0, true
+	}
+	return 0, false
+}
+
+func (m Model) tabOf(source data.StarSource) int {
+	if source == m.source {
+		return m.activeTab
+	}
+	for i, t := range m.tabs {
+		if i != m.activeTab && t.source == source {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m Model) tabSource(i int) data.StarSource {
+	if i == m.activeTab {
+		return m.source
+	}
+	return m.tabs[i].source
+}
+
+func (m Model) tabLabel(i int) string {
+	source := m.tabSource(i)
+	if source == nil || source.Login() == "" {
+		return "Your stars"
+	}
+	return source.Login() + "'s stars"
+}
+
+// openUserPrompt asks for the login whose stars to browse.
+func (m *Model) openUserPrompt() tea.Cmd {
+	if m.client == nil || m.source == nil {
+		m.status = "not connected to GitHub"
+		m.statusIsError = true
+		return nil
+	}
+	m.editor = &annotationEditor{kind: editLogin, input: m.newLineInput("Login: ", "user or organization")}
+	m.resizeEditor()
+	return m.editor.input.Focus()
+}
+
+func (m Model) resolveOwner(login string) (Model, tea.Cmd) {
+	login = strings.TrimPrefix(strings.TrimSpace(login), "@")
+	if login == "" {
+		return m, nil
+	}
+	for i := range m.tabs {
+		if strings.EqualFold(m.tabSource(i).Login(), login) {
+			m.switchTab(i)
+			return m, nil
+		}
+	}
+	m.status = "looking up " + login
+	m.statusIsError = false
+	return m, fetchOwnerCmd(m.ctx, m.client, login)
+}
+
+func (m Model) handleOwner(msg ownerMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = fmt.Sprintf("lookup failed: %v", msg.err)
+		m.statusIsError = true
+		return m, nil
+	}
+	if !msg.owner.IsOrganization {
+		return m.openUserTab(msg.owner.Login)
+	}
+	if len(msg.owner.Members) == 0 {
+		m.status = fmt.Sprintf("%s has no public members", msg.owner.Login)
+		m.statusIsError = true
+		return m, nil
+	}
+
+	items := make([]pickerItem, 0, len(msg.owner.Members))
+	for _, login := range msg.owner.Members {
+		items = append(items, pickerItem{label: login, value: login})
+	}
+	m.picker = &picker{
+		title: msg.owner.Login + " members",
+		items: items,
+		onSubmit: func(m Model, p picker) (Model, tea.Cmd) {
+			return m.openUserTab(p.selected().value)
+		},
+	}
+	return m, nil
+}
+
+// openUserTab adds a read-only tab with login's stars and starts loading
+// them. They are not cached; the tab lives as long as the session.
+func (m Model) openUserTab(login string) (Model, tea.Cmd) {
+	source := data.NewUserSource(m.client, m.tabSource(0).Host(), login)
+
+	m.saveTab()
+	m.tabs = append(m.tabs, starTab{
+		source:     source,
+		readOnly:   true,
+		cacheIndex: make(map[string]struct{}),
+		loading:    true,
+		selection:  make(map[string]struct{}),
+	})
+	m.loadTab(len(m.tabs) - 1)
+	ctx := m.startSync()
+	m.previewOffset = 0
+	m.applyFilter()
+	m.status = "loading " + login + "'s stars"
+	m.statusIsError = false
+	return m, tea.Batch(m.spinner.Tick, fetchStarsPageCmd(ctx, m.source, m.pageSize, nil))
+}
+
+func (m *Model) closeTab(i int) {
+	if i <= 0 || i >= len(m.tabs) {
+		return
+	}
+	m.saveTab()
+	if cancel := m.tabs[i].cancelSync; cancel != nil {
+		// Stop loading the closed tab's stars.
+		cancel()
+	}
+	if i == m.activeTab {
+		m.loadTab(0)
+		m.applyFilter()
+	}
+	m.tabs = append(m.tabs[:i], m.tabs[i+1:]...)
+	if m.activeTab > i {
+		m.activeTab--
+	}
+}
+
+// viewerTab returns the tab with the viewer's own stars, or -1 when the UI
+// was started on someone else's stars.
+func (m Model) viewerTab() int {
+	for i, t := range m.tabs {
+		readOnly := t.readOnly
+		if i == m.activeTab {
+			readOnly = m.readOnly
+		}
+		if !readOnly {
+			return i
+		}
+	}
+	return -1
+}
+
+// ownStarred reports whether the viewer has starred name, from any tab.
+func (m Model) ownStarred(name string) bool {
+	i := m.viewerTab()
+	if i < 0 {
+		return false
+	}
+	index := m.tabs[i].cacheIndex
+	if i == m.activeTab {
+		index = m.cacheIndex
+	}
+	_, ok := index[name]
+	return ok
+}
+
+// starToo stars the target repos of another user's tab that the viewer has
+// not starred yet. It needs the viewer's own stars to know which those are.
+func (m Model) starToo() (Model, tea.Cmd) {
+	viewer := m.viewerTab()
+	if viewer == m.activeTab {
+		return m, nil
+	}
+	if viewer < 0 {
+		m.status = "star too needs your own stars; start gh-stars without -user"
+		m.statusIsError = true
+		return m, nil
+	}
+	cmds := []tea.Cmd{}
+	for _, repo := range m.targetRepos() {
+		if m.ownStarred(repo.NameWithOwner) {
+			continue
+		}
+		cmds = append(cmds, m.starTooCmd(repo))
+	}
+	if len(cmds) == 0 {
+		m.status = "already starred"
+		m.statusIsError = false
+		return m, nil
+	}
+	m.status = fmt.Sprintf("starring %d repos", len(cmds))
+	if len(cmds) == 1 {
+		m.status = "starring"
+	}
+	m.statusIsError = false
+	return m, tea.Batch(cmds...)
+}
+
+// handleStarToo adds a newly starred repo to the top of the viewer's stars.
+func (m Model) handleStarToo(msg starTooMsg) (Model, tea.Cmd) {
+	name := msg.repo.NameWithOwner
+	if msg.err != nil {
+		m.status = fmt.Sprintf("star %s failed: %v", name, msg.err)
+		m.statusIsError = true
+		return m, nil
+	}
+	if viewer := m.viewerTab(); viewer >= 0 {
+		m, _ = m.onTab(viewer, func(m Model) (Model, tea.Cmd) {
+			if _, exists := m.cacheIndex[name]; !exists {
+				repo := msg.repo
+				repo.StarredAt = time.Now().UTC()
+				m.repos = append([]data.Repo{repo}, m.repos...)
+				m.cacheIndex[name] = struct{}{}
+				m.applyFilter()
+				m.persistCache()
+			}
+			return m, nil
+		})
+	}
+	if !m.statusIsError {
+		m.status = "starred " + name
+	}
+	return m, nil
+}
+
+func (m Model) renderTabs() string {
+	parts := make([]string, 0, len(m.tabs))
+	for i := range m.tabs {
+		label := " " + m.tabLabel(i) + " "
+		if i == m.activeTab {
+			parts = append(parts, m.styles.PanelTitle.Render(label))
+		} else {
+			parts = append(parts, m.styles.Muted.Render(label))
+		}
+	}
+	return ansi.Truncate(strings.Join(parts, m.styles.Divider.Render("│")), m.width, "…")
+}
+
+func fetchOwnerCmd(ctx context.Context, client *gh.GraphQLClient, login string) tea.Cmd {
+	return func() tea.Msg {
+		owner, err := data.FetchOwner(ctx, client, login)
+		return ownerMsg{owner: owner, err: err}
+	}
+}
+
+func (m Model) starTooCmd(repo data.Repo) tea.Cmd {
+	client := m.client
+	return m.mutationCmd(func(ctx context.Context) tea.Msg {
+		return starTooMsg{repo: repo, err: data.AddStar(ctx, client, repo)}
+	})
+}
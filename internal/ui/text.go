@@ -145,3 +145,68 @@ func renderLineWithWidth(left, right string, width int) string {
 
 	return padRight(left+strings.Repeat(" ", gap)+right, width)
 }
+
+// highlight renders text with base, switching to match for the runes at the
+// given offsets. Spaces and newlines are left unstyled so the result can
+// still be wrapped on word boundaries without splitting escape sequences.
+func highlight(text string, positions []int, base, match lipgloss.Style) string {
+	if len(positions) == 0 {
+		return renderWords(text, base)
+	}
+
+	hit := make(map[int]struct{}, len(positions))
+	for _, p := range positions {
+		hit[p] = struct{}{}
+	}
+	match = match.Inherit(base)
+
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		style := base
+		if runMatched {
+			style = match
+		}
+		b.WriteString(renderWords(string(run), style))
+		run = run[:0]
+	}
+
+	for i, r := range []rune(text) {
+		_, matched := hit[i]
+		if matched != runMatched {
+			flush()
+			runMatched = matched
+		}
+		run = append(run, r)
+	}
+	flush()
+
+	return b.String()
+}
+
+func renderWords(text string, style lipgloss.Style) string {
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	word := strings.Builder{}
+	for _, r := range text {
+		if r == ' ' || r == '\n' {
+			if word.Len() > 0 {
+				b.WriteString(style.Render(word.String()))
+				word.Reset()
+			}
+			b.WriteRune(r)
+			continue
+		}
+		word.WriteRune(r)
+	}
+	if word.Len() > 0 {
+		b.WriteString(style.Render(word.String()))
+	}
+	return b.String()
+}
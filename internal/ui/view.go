@@ -3,9 +3,14 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+	"github.com/viniciussoares/github-stars-tui/internal/search"
+	"github.com/viniciussoares/github-stars-tui/internal/workspace"
 )
 
 func (m Model) View() string {
@@ -33,14 +38,38 @@ func (m Model) renderError() string {
 
 func (m Model) renderHeader() string {
 	search := m.searchInput.View()
-	return m.styles.SearchBox.Width(m.width - 2*searchBoxPadding).Render(search)
+	header := m.styles.SearchBox.Width(m.width - 2*searchBoxPadding).Render(search)
+	if len(m.tabs) > 1 {
+		header = m.renderTabs() + "\n" + header
+	}
+	return header
 }
 
 func (m Model) renderBody() string {
+	if m.showStats {
+		height := m.listHeight()
+		content := m.renderStats(m.panelContentHeight(height), m.panelContentWidth(m.width))
+		return m.panelStyle(m.width, height).Render(content)
+	}
+
+	panels := m.renderPanels()
+	if m.facetWidth <= 0 {
+		return panels
+	}
+
+	height := m.listHeight()
+	facetPanel := m.panelStyle(m.facetWidth, height).Render(m.renderFacets(m.panelContentHeight(height), m.panelContentWidth(m.facetWidth)))
+	return joinColumns(facetPanel, panels, "", height, m.facetWidth, m.listWidth+m.previewWidth)
+}
+
+func (m Model) renderPanels() string {
 	height := m.listHeight()
 	listContentHeight := m.panelContentHeight(height)
 	listContentWidth := m.panelContentWidth(m.listWidth)
-	listContent := m.renderList(listContentHeight, listContentWidth)
+	listContent, modal := m.renderModal(listContentHeight, listContentWidth)
+	if !modal || m.previewWidth > 0 {
+		listContent = m.renderList(listContentHeight, listContentWidth)
+	}
 	listPanel := m.panelStyle(m.listWidth, height).Render(listContent)
 
 	if m.previewWidth <= 0 {
@@ -49,12 +78,27 @@ func (m Model) renderBody() string {
 
 	previewContentHeight := m.panelContentHeight(height)
 	previewContentWidth := m.panelContentWidth(m.previewWidth)
-	previewContent := m.renderPreview(previewContentHeight, previewContentWidth)
+	previewContent, modal := m.renderModal(previewContentHeight, previewContentWidth)
+	if !modal {
+		previewContent = m.renderPreview(previewContentHeight, previewContentWidth)
+	}
 	previewPanel := m.panelStyle(m.previewWidth, height).Render(previewContent)
 
 	return joinColumns(listPanel, previewPanel, "", height, m.listWidth, m.previewWidth)
 }
 
+// renderModal renders the open picker or editor, which take over the
+// preview panel (or the list panel when the preview is hidden).
+func (m Model) renderModal(height, width int) (string, bool) {
+	switch {
+	case m.editor != nil:
+		return m.renderEditor(height, width), true
+	case m.picker != nil:
+		return m.renderPicker(height, width), true
+	}
+	return "", false
+}
+
 func (m Model) panelStyle(width, height int) lipgloss.Style {
 	innerWidth := max(1, width-(2*panelPaddingX))
 	innerHeight := max(1, height-(2*panelBorderWidth)-(2*panelPaddingY))
@@ -65,7 +109,16 @@ func (m Model) renderFooter() string {
 	key := m.styles.FooterKey.Render
 	txt := m.styles.Footer.Render
 	sep := txt(" · ")
-	help := key("q") + txt(" quit") + sep + key("/") + txt(" search") + sep + key("↵") + txt(" open") + sep + key("y") + txt(" copy") + sep + key("r") + txt(" refresh") + sep + key("s") + txt(" sort") + sep + key("j/k") + txt(" move") + sep + key("g/G") + txt(" top/bottom")
+	enterAction := " open"
+	if m.pickMode {
+		enterAction = " pick"
+	}
+	k := m.keys.Help
+	refresh := key(k(actionRefresh)) + txt(" refresh")
+	if m.loading {
+		refresh = key(k(actionCancelSync)) + txt(" cancel sync")
+	}
+	help := key(k(actionQuit)) + txt(" quit") + sep + key(k(actionSearch)) + txt(" search") + sep + key(k(actionOpen)) + txt(enterAction) + sep + key(k(actionCopy)) + txt(" copy") + sep + refresh + sep + key(k(actionSort)) + txt(" sort") + sep + key(k(actionDown)+"/"+k(actionUp)) + txt(" move") + sep + key(k(actionTop)+"/"+k(actionBottom)) + txt(" top/bottom")
 
 	status := strings.TrimSpace(m.status)
 	if status == "" {
@@ -80,13 +133,39 @@ func (m Model) renderFooter() string {
 		status = status + "  " + countText
 	}
 
+	if len(m.selection) > 0 {
+		status = status + fmt.Sprintf("  [%d selected]", len(m.selection))
+	}
+
 	if m.sortMode != "default" {
 		status = status + "  [" + m.sortMode + "]"
 	}
 
+	if text := m.rateLimitText(); text != "" {
+		status = status + "  " + text
+	}
+
+	if name := data.SourceName(m.source); name != "" {
+		status = status + "  [" + name + "]"
+	}
+
+	if label := m.listLabel(); label != "" {
+		status = status + "  [" + label + "]"
+	}
+
+	if label := m.facetLabel(); label != "" {
+		status = status + "  [" + label + "]"
+	}
+
 	left := help
+	if m.prompt != nil {
+		left = key(m.prompt.text) + txt(" y/n")
+	}
 	rightStyle := m.styles.Footer
-	if m.statusIsError {
+	if m.queryErr != nil {
+		status = "query: " + m.queryErr.Error()
+		rightStyle = m.styles.FooterError
+	} else if m.statusIsError {
 		rightStyle = m.styles.FooterError
 	}
 	right := rightStyle.Render(status)
@@ -113,9 +192,17 @@ func (m Model) renderList(height, width int) string {
 		return strings.Join(lines, "\n")
 	}
 
+	// Reserve a gutter for selection markers while anything is selected.
+	gutter := ""
+	if len(m.selection) > 0 || m.rangeAnchor != "" {
+		gutter = "  "
+	}
+	rowWidth := width - lipgloss.Width(gutter)
+
 	start := m.offset
 	rowsVisible := max(1, (bodyHeight+1)/listRowHeight)
 	end := min(start+rowsVisible, len(m.filtered))
+	now := time.Now()
 	for i := start; i < end; i++ {
 		idx := m.filtered[i]
 		if idx < 0 || idx >= len(m.repos) {
@@ -124,31 +211,61 @@ func (m Model) renderList(height, width int) string {
 		repo := m.repos[idx]
 		selected := i == m.cursor
 
-		metaParts := []string{}
+		metaParts := healthBadges(repo, repo.Health(now, m.inactiveAfter))
+		if m.activeTab > 0 && m.ownStarred(repo.NameWithOwner) {
+			metaParts = append(metaParts, "starred ✓")
+		}
 		if repo.IsFork {
 			metaParts = append(metaParts, "fork 🍴")
 		}
 		if repo.PrimaryLanguage != "" {
 			metaParts = append(metaParts, repo.PrimaryLanguage+" 🧪")
 		}
+		annotation := m.annotations[repo.NameWithOwner]
+		if strings.TrimSpace(annotation.Note) != "" {
+			metaParts = append(metaParts, "📝")
+		}
+		if m.cloned[repo.NameWithOwner] {
+			metaParts = append(metaParts, "📂")
+		}
 		metaParts = append(metaParts, fmt.Sprintf("%6d ⭐", repo.Stars))
-		name := repo.NameWithOwner
 		meta := strings.Join(metaParts, "  ")
-		line1 := renderLineWithWidth(truncate(name, width), meta, width)
+
+		primary := m.styles.ListRow
+		secondary := m.styles.ListRowSecondary
+		if selected {
+			primary = m.styles.ListRowSelected
+			secondary = m.styles.ListRowSelectedSecondary
+		}
+
+		namePositions := m.matchPositions(idx, search.FieldName)
+		line1 := m.renderRowTitle(repo.NameWithOwner, namePositions, meta, rowWidth, primary)
 
 		desc := repo.Description
+		descPositions := m.matchPositions(idx, search.FieldDescription)
 		if desc == "" {
 			desc = "-"
+			descPositions = nil
 		}
-		desc = truncate(desc, width)
-		line2 := padRight(desc, width)
-
-		if selected {
-			line1 = m.styles.ListRowSelected.Render(line1)
-			line2 = m.styles.ListRowSelectedSecondary.Render(line2)
-		} else {
-			line1 = m.styles.ListRow.Render(line1)
-			line2 = m.styles.ListRowSecondary.Render(line2)
+		tagText := ""
+		if len(annotation.Tags) > 0 {
+			tagText = truncate("#"+strings.Join(annotation.Tags, " #"), rowWidth/2) + " "
+		}
+		desc = truncate(desc, rowWidth-lipgloss.Width(tagText))
+		line2 := m.styles.Tag.Inherit(secondary).Render(tagText)
+		line2 += highlight(desc, descPositions, secondary, m.styles.MatchHighlight)
+		line2 += secondary.Render(strings.Repeat(" ", max(0, rowWidth-lipgloss.Width(tagText)-lipgloss.Width(desc))))
+
+		if gutter != "" {
+			marker := gutter
+			switch {
+			case m.isSelected(repo.NameWithOwner):
+				marker = "● "
+			case repo.NameWithOwner == m.rangeAnchor:
+				marker = "◌ "
+			}
+			line1 = m.styles.SelectionMarker.Render(marker) + line1
+			line2 = gutter + line2
 		}
 
 		lines = append(lines, line1, line2)
@@ -168,6 +285,19 @@ func (m Model) renderList(height, width int) string {
 	return strings.Join(lines, "\n")
 }
 
+// renderRowTitle lays out a list row's name on the left and meta on the
+// right, highlighting matched runes in the (possibly truncated) name.
+func (m Model) renderRowTitle(name string, positions []int, meta string, width int, base lipgloss.Style) string {
+	metaWidth := lipgloss.Width(meta)
+	if metaWidth >= width {
+		return base.Render(padRight(truncate(meta, width), width))
+	}
+
+	name = truncate(name, max(0, width-metaWidth-1))
+	gap := max(1, width-lipgloss.Width(name)-metaWidth)
+	return highlight(name, positions, base, m.styles.MatchHighlight) + base.Render(strings.Repeat(" ", gap)+meta)
+}
+
 func (m Model) renderPreview(height, width int) string {
 	if width <= 0 || height <= 0 {
 		return ""
@@ -196,11 +326,38 @@ func (m Model) renderPreview(height, width int) string {
 		return strings.Join(lines, "\n")
 	}
 
-	// Repository name
-	nameLines := wrapLines([]string{repo.NameWithOwner}, width)
-	for _, line := range nameLines {
-		lines = append(lines, m.styles.PreviewTitle.Render(line))
+	lines = m.previewLines(width)
+	offset := clamp(m.previewOffset, 0, max(0, len(lines)-height))
+	lines = lines[offset:]
+
+	for i := range lines {
+		lines[i] = padRight(lines[i], width)
 	}
+
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// previewLines returns the full, unscrolled preview content for the selected
+// repo.
+func (m Model) previewLines(width int) []string {
+	repo := m.selectedRepo()
+	if repo == nil {
+		return nil
+	}
+
+	lines := []string{}
+	idx := m.filtered[m.cursor]
+
+	// Repository name
+	name := highlight(repo.NameWithOwner, m.matchPositions(idx, search.FieldName), m.styles.PreviewTitle, m.styles.MatchHighlight)
+	lines = append(lines, wrapLines([]string{name}, width)...)
 	lines = append(lines, "")
 
 	// Meta info (language, stars, date, fork)
@@ -212,49 +369,120 @@ func (m Model) renderPreview(height, width int) string {
 	if !repo.UpdatedAt.IsZero() {
 		metaParts = append(metaParts, "🕒 "+repo.UpdatedAt.Format("2006-01-02"))
 	}
+	if !repo.PushedAt.IsZero() {
+		metaParts = append(metaParts, "📤 pushed "+repo.PushedAt.Format("2006-01-02"))
+	}
 	if repo.IsFork {
 		metaParts = append(metaParts, "🍴 fork")
 	}
+	if repo.IsMirror {
+		metaParts = append(metaParts, "🪞 mirror")
+	}
 	if len(metaParts) > 0 {
 		metaLines := wrapLines([]string{strings.Join(metaParts, "  ")}, width)
 		for _, line := range metaLines {
 			lines = append(lines, m.styles.Muted.Render(line))
 		}
 	}
+	lines = append(lines, m.detailsLines(repo.NameWithOwner, width)...)
+	if warning := healthWarning(*repo, time.Now(), m.inactiveAfter); warning != "" {
+		for _, line := range wrapLines([]string{warning}, width) {
+			lines = append(lines, m.styles.FooterError.Render(line))
+		}
+	}
 	lines = append(lines, "")
 
 	// Description
 	desc := repo.Description
 	if strings.TrimSpace(desc) != "" {
+		desc = highlight(desc, m.matchPositions(idx, search.FieldDescription), lipgloss.NewStyle(), m.styles.MatchHighlight)
 		descLines := wrapLines([]string{desc}, width)
 		lines = append(lines, descLines...)
 		lines = append(lines, "")
 	}
 
-	// Topics
-	if len(repo.Topics) > 0 {
-		topicLines := wrapLines([]string{strings.Join(repo.Topics, ", ")}, width)
-		for _, line := range topicLines {
+	// Topics; the star list only carries the first few, so prefer the full
+	// list once details are loaded. Match positions index into the cached
+	// topics, which the full list starts with.
+	topicText := strings.Join(repo.Topics, search.TopicSeparator)
+	if state, ok := m.details[repo.NameWithOwner]; ok && len(state.details.Topics) > len(repo.Topics) {
+		full := strings.Join(state.details.Topics, search.TopicSeparator)
+		if strings.HasPrefix(full, topicText) {
+			topicText = full
+		}
+	}
+	if topicText != "" {
+		topics := highlight(topicText, m.matchPositions(idx, search.FieldTopics), m.styles.Muted, m.styles.MatchHighlight)
+		lines = append(lines, wrapLines([]string{topics}, width)...)
+		lines = append(lines, "")
+	}
+
+	// Local tags and note
+	annotation := m.annotations[repo.NameWithOwner]
+	if len(annotation.Tags) > 0 {
+		tags := highlight(strings.Join(annotation.Tags, search.TagSeparator), m.matchPositions(idx, search.FieldTags), m.styles.Tag, m.styles.MatchHighlight)
+		lines = append(lines, wrapLines([]string{"🏷 " + tags}, width)...)
+		lines = append(lines, "")
+	}
+	if strings.TrimSpace(annotation.Note) != "" {
+		// Match offsets refer to the note as stored, so it is not trimmed here.
+		note := highlight(annotation.Note, m.matchPositions(idx, search.FieldNote), lipgloss.NewStyle(), m.styles.MatchHighlight)
+		noteLines := wrapLines(strings.Split("📝 "+note, "\n"), width)
+		lines = append(lines, noteLines...)
+		lines = append(lines, "")
+	}
+
+	// Star lists
+	if names := m.repoListNames(*repo); len(names) > 0 {
+		for _, line := range wrapLines([]string{"📋 " + strings.Join(names, ", ")}, width) {
 			lines = append(lines, m.styles.Muted.Render(line))
 		}
 		lines = append(lines, "")
 	}
 
-	// URL
+	// URL and local clone
 	lines = append(lines, m.styles.Muted.Render(repo.URL))
+	if m.cloned[repo.NameWithOwner] {
+		if path, err := workspace.Path(m.cloneLayout, *repo); err == nil {
+			for _, line := range wrapLines([]string{"📂 " + path}, width) {
+				lines = append(lines, m.styles.Muted.Render(line))
+			}
+		}
+	}
+	lines = append(lines, "")
 
-	for i := range lines {
-		lines[i] = padRight(lines[i], width)
+	// README
+	lines = append(lines, m.styles.PanelTitle.Render("README"), "")
+	state, ok := m.readmes[repo.NameWithOwner]
+	switch {
+	case !ok || state.loading:
+		lines = append(lines, m.styles.Muted.Render("loading README..."))
+	case state.err != nil:
+		for _, line := range wrapLines([]string{"README unavailable: " + state.err.Error()}, width) {
+			lines = append(lines, m.styles.FooterError.Render(line))
+		}
+	case strings.TrimSpace(state.readme.Text) == "":
+		lines = append(lines, m.styles.Muted.Render("no README"))
+	default:
+		lines = append(lines, m.readmeLines(repo.NameWithOwner, state.readme.Text, width)...)
 	}
 
-	for len(lines) < height {
-		lines = append(lines, strings.Repeat(" ", width))
+	return lines
+}
+
+// readmeLines memoizes rendered READMEs per repo and width, since View runs
+// on every spinner tick.
+func (m Model) readmeLines(name, text string, width int) []string {
+	key := fmt.Sprintf("%s@%d", name, width)
+	if lines, ok := m.readmeRendered[key]; ok {
+		return lines
 	}
-	if len(lines) > height {
-		lines = lines[:height]
+	if len(m.readmeRendered) >= 64 {
+		clear(m.readmeRendered)
 	}
-
-	return strings.Join(lines, "\n")
+	lines := renderMarkdown(text, width, m.styles)
+	m.readmeRendered[key] = lines
+	return lines
 }
 
 func (m Model) renderLine(left, right string) string {
@@ -273,7 +501,7 @@ func (m Model) countText() string {
 		}
 		return fmt.Sprintf("%d loaded", len(m.repos))
 	}
-	if strings.TrimSpace(m.searchInput.Value()) != "" {
+	if strings.TrimSpace(m.searchInput.Value()) != "" || m.activeListMembers != nil || m.hasFacetFilter() {
 		return fmt.Sprintf("%d/%d match", len(m.filtered), m.totalCount)
 	}
 	return fmt.Sprintf("%d total", m.totalCount)
@@ -0,0 +1,136 @@
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+const DefaultLayout = "~/src/{owner}/{name}"
+
+var Protocols = []string{"https", "ssh"}
+
+func ValidateProtocol(protocol string) error {
+	for _, candidate := range Protocols {
+		if candidate == protocol {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown clone protocol %q (want %s)", protocol, strings.Join(Protocols, " or "))
+}
+
+// Path expands layout for repo. The layout may start with ~ and use the
+// {host}, {owner} and {name} placeholders.
+func Path(layout string, repo data.Repo) (string, error) {
+	owner, name, ok := strings.Cut(repo.NameWithOwner, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid repository name %q", repo.NameWithOwner)
+	}
+	if layout == "" {
+		layout = DefaultLayout
+	}
+
+	if layout == "~" || strings.HasPrefix(layout, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		layout = home + layout[1:]
+	}
+
+	path := strings.NewReplacer(
+		"{host}", Host(repo),
+		"{owner}", owner,
+		"{name}", name,
+	).Replace(layout)
+	return filepath.Clean(path), nil
+}
+
+func Host(repo data.Repo) string {
+	if parsed, err := url.Parse(repo.URL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return "github.com"
+}
+
+func CloneURL(repo data.Repo, protocol string) string {
+	if protocol == "ssh" {
+		return "git@" + Host(repo) + ":" + repo.NameWithOwner + ".git"
+	}
+	return strings.TrimSuffix(repo.URL, "/") + ".git"
+}
+
+// IsCloned reports whether path holds a git checkout.
+func IsCloned(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// Clone runs git clone into dir, reporting each progress line git prints.
+// A failed or cancelled clone leaves nothing behind at dir.
+func Clone(ctx context.Context, cloneURL, dir string, progress func(string)) error {
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--progress", cloneURL, dir)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// git redraws progress with \r, so split on both line endings.
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	last := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		last = line
+		if progress != nil {
+			progress(line)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		// dir did not exist before, so whatever is there is a partial checkout.
+		_ = os.RemoveAll(dir)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && last != "" {
+			return errors.New(strings.TrimPrefix(last, "fatal: "))
+		}
+		return err
+	}
+	return nil
+}
+
+func scanProgressLines(buf []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(buf, "\r\n"); i >= 0 {
+		return i + 1, buf[:i], nil
+	}
+	if atEOF && len(buf) > 0 {
+		return len(buf), buf, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,122 @@
+package workspace
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/viniciussoares/github-stars-tui/internal/data"
+)
+
+func TestPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	repo := data.Repo{NameWithOwner: "charmbracelet/bubbletea", URL: "https://github.com/charmbracelet/bubbletea"}
+	enterprise := data.Repo{NameWithOwner: "team/tool", URL: "https://git.example.com/team/tool"}
+
+	tests := []struct {
+		layout string
+		repo   data.Repo
+		want   string
+	}{
+		{"", repo, filepath.Join(home, "src", "charmbracelet", "bubbletea")},
+		{"~/code/{name}", repo, filepath.Join(home, "code", "bubbletea")},
+		{"~", repo, home},
+		{"/srv/{host}/{owner}/{name}", repo, "/srv/github.com/charmbracelet/bubbletea"},
+		{"/srv/{host}/{owner}/{name}", enterprise, "/srv/git.example.com/team/tool"},
+		{"/srv/{owner}-{name}/", repo, "/srv/charmbracelet-bubbletea"},
+		{"~user/{name}", repo, "~user/bubbletea"},
+		{"relative/{owner}/../{name}", repo, filepath.Join("relative", "bubbletea")},
+	}
+
+	for _, tt := range tests {
+		got, err := Path(tt.layout, tt.repo)
+		if err != nil {
+			t.Fatalf("Path(%q, %s): %v", tt.layout, tt.repo.NameWithOwner, err)
+		}
+		if got != tt.want {
+			t.Errorf("Path(%q, %s) = %q, want %q", tt.layout, tt.repo.NameWithOwner, got, tt.want)
+		}
+	}
+
+	if _, err := Path("", data.Repo{NameWithOwner: "noslash"}); err == nil {
+		t.Error("Path accepted a name without an owner")
+	}
+}
+
+func TestCloneURL(t *testing.T) {
+	tests := []struct {
+		repo     data.Repo
+		protocol string
+		want     string
+	}{
+		{data.Repo{NameWithOwner: "junegunn/fzf", URL: "https://github.com/junegunn/fzf"}, "https", "https://github.com/junegunn/fzf.git"},
+		{data.Repo{NameWithOwner: "junegunn/fzf", URL: "https://github.com/junegunn/fzf/"}, "https", "https://github.com/junegunn/fzf.git"},
+		{data.Repo{NameWithOwner: "junegunn/fzf", URL: "https://github.com/junegunn/fzf"}, "ssh", "git@github.com:junegunn/fzf.git"},
+		{data.Repo{NameWithOwner: "team/tool", URL: "https://git.example.com/team/tool"}, "ssh", "git@git.example.com:team/tool.git"},
+		{data.Repo{NameWithOwner: "team/tool"}, "ssh", "git@github.com:team/tool.git"},
+	}
+
+	for _, tt := range tests {
+		if got := CloneURL(tt.repo, tt.protocol); got != tt.want {
+			t.Errorf("CloneURL(%s, %s) = %q, want %q", tt.repo.URL, tt.protocol, got, tt.want)
+		}
+	}
+}
+
+func TestValidateProtocol(t *testing.T) {
+	for _, protocol := range Protocols {
+		if err := ValidateProtocol(protocol); err != nil {
+			t.Errorf("ValidateProtocol(%q): %v", protocol, err)
+		}
+	}
+	err := ValidateProtocol("git")
+	if want := `unknown clone protocol "git" (want https or ssh)`; err == nil || err.Error() != want {
+		t.Errorf("ValidateProtocol(git) = %v, want %q", err, want)
+	}
+}
+
+func TestScanProgressLines(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("Cloning into 'x'...\nReceiving 10%\rReceiving 100%\r\ndone"))
+	scanner.Split(scanProgressLines)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	want := []string{"Cloning into 'x'...", "Receiving 10%", "Receiving 100%", "", "done"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
+
+func TestCloneFailureLeavesNothing(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	dir := filepath.Join(root, "owner", "missing")
+
+	err := Clone(context.Background(), filepath.Join(root, "does-not-exist"), dir, nil)
+	if err == nil {
+		t.Fatal("Clone of a missing repository succeeded")
+	}
+	if strings.HasPrefix(err.Error(), "fatal: ") {
+		t.Errorf("error %q keeps git's fatal: prefix", err)
+	}
+	if _, statErr := os.Stat(dir); !os.IsNotExist(statErr) {
+		t.Errorf("%s was left behind: %v", dir, statErr)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Clone(context.Background(), "unused", dir, nil); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Clone into an existing directory = %v, want already exists", err)
+	}
+}